@@ -0,0 +1,349 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// aggregation frequencies
+const (
+	FrequencyMonthly   = "monthly"
+	FrequencyQuarterly = "quarterly"
+	FrequencyAnnual    = "annual"
+)
+
+// aggregation methods
+const (
+	AggregateSum     = "sum"     // flows, e.g. claims or fees paid in the period
+	AggregateEnd     = "end"     // balances, value at the last month of the period
+	AggregateAverage = "average" // mean of the months in the period
+)
+
+// aggregation groups
+const (
+	GroupByScenario = "scenario" // one series per scenario (from a Scenario column or the file name)
+	GroupByBlock    = "block"    // one series per block, summed over files
+	GroupByNone     = "none"     // everything summed into a single series
+)
+
+// AggregationRequest describes how monthly output files are rolled up
+type AggregationRequest struct {
+	Path               string   // output folder, or a single output file
+	FilterString       string   // file name filter, as in ReadFiles
+	WalkSubdirectories bool     // as in ReadFiles
+	Columns            []string // metrics to aggregate
+	TimeColumn         string   // month column, detected when empty; row order is used if there is none
+	BlockColumn        string   // block column, detected when empty (used by GroupBy block and Blocks)
+	Blocks             []int    // only keep rows of these blocks, e.g. the config's CorpBlocks
+	Frequency          string   // monthly, quarterly or annual
+	Method             string   // sum, end or average
+	GroupBy            string   // scenario (default), block or none
+	DiscountRate       float64  // annual effective rate used for PresentValue
+}
+
+// AggregatedSeries is one metric for one group, rolled up to the requested frequency
+type AggregatedSeries struct {
+	Group        string
+	Column       string
+	Periods      []int // period number, 0 holds month 0 (the valuation date) when present
+	Values       Float64Array
+	PresentValue float64 // of the monthly values at DiscountRate, month m discounted by m/12 years
+}
+
+type AggregationResult struct {
+	Frequency string
+	Method    string
+	GroupBy   string
+	Files     []string // files that were aggregated
+	Series    []AggregatedSeries
+}
+
+var (
+	timeColumnNames  = []string{"Month", "Time", "Period", "t", "TimeStep", "ProjectionMonth"}
+	blockColumnNames = []string{"Block", "CorpBlock", "corp_block", "BlockID", "Block_ID"}
+	scenarioNames    = []string{"Scenario", "ScenarioID", "Scenario_ID", "Scen"}
+)
+
+// AggregateOutput rolls monthly projection outputs up to quarters or years, per scenario or
+// block, so charts only receive the aggregated series
+func (a *App) AggregateOutput(request AggregationRequest) (*AggregationResult, error) {
+	if request.Frequency == "" {
+		request.Frequency = FrequencyAnnual
+	}
+	months, err := monthsPerPeriod(request.Frequency)
+	if err != nil {
+		return nil, err
+	}
+	if request.Method == "" {
+		request.Method = AggregateSum
+	}
+	if request.Method != AggregateSum && request.Method != AggregateEnd && request.Method != AggregateAverage {
+		return nil, fmt.Errorf("unknown aggregation method %q", request.Method)
+	}
+	if request.GroupBy == "" {
+		request.GroupBy = GroupByScenario
+	}
+	if request.GroupBy != GroupByScenario && request.GroupBy != GroupByBlock && request.GroupBy != GroupByNone {
+		return nil, fmt.Errorf("unknown aggregation group %q", request.GroupBy)
+	}
+	if len(request.Columns) == 0 {
+		return nil, fmt.Errorf("no columns to aggregate")
+	}
+
+	tables, err := a.readAggregationTables(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// monthly values per group and column, keyed by month
+	monthly := make(map[string]map[string]map[int]float64)
+	result := &AggregationResult{Frequency: request.Frequency, Method: request.Method, GroupBy: request.GroupBy}
+
+	for i := range tables {
+		table := &tables[i]
+		if err := accumulateTable(table, request, monthly); err != nil {
+			return nil, fmt.Errorf("%s: %w", table.Name, err)
+		}
+		result.Files = append(result.Files, table.Path)
+	}
+
+	groups := make([]string, 0, len(monthly))
+	for group := range monthly {
+		groups = append(groups, group)
+	}
+	sortGroups(groups)
+
+	for _, group := range groups {
+		for _, column := range request.Columns {
+			values, ok := monthly[group][column]
+			if !ok {
+				continue
+			}
+			result.Series = append(result.Series, rollUp(group, column, values, months, request))
+		}
+	}
+
+	return result, nil
+}
+
+func monthsPerPeriod(frequency string) (int, error) {
+	switch frequency {
+	case FrequencyMonthly:
+		return 1, nil
+	case FrequencyQuarterly:
+		return 3, nil
+	case FrequencyAnnual:
+		return 12, nil
+	}
+	return 0, fmt.Errorf("unknown aggregation frequency %q", frequency)
+}
+
+func (a *App) readAggregationTables(request AggregationRequest) ([]OutputTable, error) {
+	info, err := os.Stat(request.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		table, err := a.ReadTable(request.Path)
+		if err != nil {
+			return nil, err
+		}
+		return []OutputTable{*table}, nil
+	}
+
+	return a.ReadTables(request.Path, request.FilterString, request.WalkSubdirectories)
+}
+
+// fileScenarioNumber is the scenario of a pALM output file name, e.g. 3 for
+// DebugInfo_Scenario_my_run_3.csv, matched as post processing matches it
+func fileScenarioNumber(name string) (string, bool) {
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	match := scenarioPartPattern.FindStringSubmatch(base)
+	if match == nil {
+		return "", false
+	}
+	return match[2], true
+}
+
+// accumulateTable adds the monthly values of a table to their group
+func accumulateTable(table *OutputTable, request AggregationRequest, monthly map[string]map[string]map[int]float64) error {
+	timeColumn := findColumn(table, request.TimeColumn, timeColumnNames)
+	if request.TimeColumn != "" && timeColumn == nil {
+		return fmt.Errorf("time column %q not found", request.TimeColumn)
+	}
+
+	blockColumn := findColumn(table, request.BlockColumn, blockColumnNames)
+	if (request.GroupBy == GroupByBlock || len(request.Blocks) > 0) && blockColumn == nil {
+		return fmt.Errorf("no block column found")
+	}
+
+	scenarioColumn := findColumn(table, "", scenarioNames)
+	fileScenario := table.Name
+	if scenario, ok := fileScenarioNumber(table.Name); ok {
+		fileScenario = scenario
+	}
+
+	keepBlock := make(map[int]bool, len(request.Blocks))
+	for _, block := range request.Blocks {
+		keepBlock[block] = true
+	}
+
+	for _, name := range request.Columns {
+		column := table.Column(name)
+		if column == nil {
+			continue // not every file of a folder has every metric
+		}
+		if column.Type == ColumnTypeString {
+			return fmt.Errorf("column %q is not numeric", name)
+		}
+
+		for row := 0; row < table.RowCount; row++ {
+			month := row + 1
+			if timeColumn != nil {
+				t := cellNumber(timeColumn, row)
+				if math.IsNaN(t) {
+					continue
+				}
+				month = int(t)
+			}
+
+			var block string
+			if blockColumn != nil {
+				b := cellNumber(blockColumn, row)
+				if len(keepBlock) > 0 && (math.IsNaN(b) || !keepBlock[int(b)]) {
+					continue
+				}
+				block = cellString(blockColumn, row)
+			}
+
+			var group string
+			switch request.GroupBy {
+			case GroupByBlock:
+				group = block
+			case GroupByNone:
+				group = "total"
+			default:
+				group = fileScenario
+				if scenarioColumn != nil {
+					group = cellString(scenarioColumn, row)
+				}
+			}
+
+			value := cellNumber(column, row)
+			if math.IsNaN(value) {
+				continue
+			}
+
+			if monthly[group] == nil {
+				monthly[group] = make(map[string]map[int]float64)
+			}
+			if monthly[group][name] == nil {
+				monthly[group][name] = make(map[int]float64)
+			}
+			monthly[group][name][month] += value
+		}
+	}
+
+	return nil
+}
+
+// rollUp turns monthly values into per period values, and computes their present value
+func rollUp(group string, column string, values map[int]float64, months int, request AggregationRequest) AggregatedSeries {
+	series := AggregatedSeries{Group: group, Column: column}
+
+	sortedMonths := make([]int, 0, len(values))
+	for month := range values {
+		sortedMonths = append(sortedMonths, month)
+	}
+	sort.Ints(sortedMonths)
+
+	counts := make(map[int]int)
+	for _, month := range sortedMonths {
+		value := values[month]
+		series.PresentValue += value / math.Pow(1+request.DiscountRate, float64(month)/12)
+
+		// month 0 is its own period, months 1..n fall in period ceil(month / n)
+		period := (month + months - 1) / months
+		if month <= 0 {
+			period = 0
+		}
+
+		last := len(series.Periods) - 1
+		if last < 0 || series.Periods[last] != period {
+			series.Periods = append(series.Periods, period)
+			series.Values = append(series.Values, 0)
+			last++
+		}
+
+		switch request.Method {
+		case AggregateEnd:
+			series.Values[last] = value // months are sorted, the last one wins
+		default:
+			series.Values[last] += value
+		}
+		counts[period]++
+	}
+
+	if request.Method == AggregateAverage {
+		for i, period := range series.Periods {
+			series.Values[i] /= float64(counts[period])
+		}
+	}
+
+	return series
+}
+
+// findColumn returns the named column, or the first of the candidates present when name is empty
+func findColumn(table *OutputTable, name string, candidates []string) *TableColumn {
+	if name != "" {
+		return table.Column(name)
+	}
+	for _, candidate := range candidates {
+		if column := table.Column(candidate); column != nil && column.Type != ColumnTypeEmpty {
+			return column
+		}
+	}
+	return nil
+}
+
+func cellNumber(column *TableColumn, row int) float64 {
+	if row < len(column.Values) {
+		return column.Values[row]
+	}
+	if row < len(column.Strings) {
+		value, _, _ := parseNumber(column.Strings[row])
+		return value
+	}
+	return math.NaN()
+}
+
+func cellString(column *TableColumn, row int) string {
+	if row < len(column.Strings) {
+		return column.Strings[row]
+	}
+	if row < len(column.Values) && !math.IsNaN(column.Values[row]) {
+		return strconv.FormatFloat(column.Values[row], 'f', -1, 64)
+	}
+	return ""
+}
+
+// sortGroups orders numeric groups (scenarios, blocks) numerically, the rest alphabetically
+func sortGroups(groups []string) {
+	sort.Slice(groups, func(i, j int) bool {
+		a, errA := strconv.Atoi(strings.TrimSpace(groups[i]))
+		b, errB := strconv.Atoi(strings.TrimSpace(groups[j]))
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		if (errA == nil) != (errB == nil) {
+			return errA == nil
+		}
+		return groups[i] < groups[j]
+	})
+}
@@ -2,18 +2,15 @@ package main
 
 import (
 	"bufio"
-	"bytes"
 	"context"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	stdruntime "runtime"
-	"strings"
-	"syscall"
+	"sync"
+	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"github.com/yosuke-furukawa/json5/encoding/json5"
@@ -41,10 +38,27 @@ type Config struct {
 	PythonLiabilityConfigScript  string `json:"pythonLiabilityConfigScript"`
 	PythonSpreadAssumptionScript string `json:"pythonSpreadAssumptionScript"`
 
+	// run pythonParserScript after pALM instead of the built in post processing
+	UsePythonParser bool `json:"usePythonParser"`
+
 	// paths for reading/creating ESG scenario configs
 	BaseScenarioConfigPath       string `json:"baseScenarioConfigPath"`
 	ScenarioConfigsPath          string `json:"scenarioConfigsPath"`
 	PythonGenerateScenarioScript string `json:"pythonGenerateScenarioScript"`
+
+	// generate scenarios with pythonGenerateScenarioScript instead of the built in generator
+	UsePythonGenerator bool `json:"usePythonGenerator"`
+
+	// python interpreter used for the scripts above, the first non-empty option wins.
+	// pythonScriptInterpreters overrides it per script file name with either a path to
+	// a python executable, a venv directory or "conda:<env>"
+	PythonInterpreterPath    string            `json:"pythonInterpreterPath"`
+	PythonVenvPath           string            `json:"pythonVenvPath"`
+	PythonCondaEnv           string            `json:"pythonCondaEnv"`
+	PythonScriptInterpreters map[string]string `json:"pythonScriptInterpreters"`
+
+	// folder holding regression baselines and their run history, ./regression when empty
+	RegressionSuitePath string `json:"regressionSuitePath"`
 }
 
 type ScenarioConfig struct {
@@ -55,6 +69,7 @@ type ScenarioConfig struct {
 	NumberOfYearsInner         int                `json:"NumberOfYearsInner"`
 	MonthPerYear               int                `json:"monthPerYear"`
 	Extrapolation              string             `json:"Extrapolation"`
+	Interpolation              string             `json:"Interpolation,omitempty"`
 	UFROuter                   float64            `json:"UFROuter"`
 	UFROuterStartMonth         int                `json:"UFROuterStartMonth"`
 	UFRInner                   float64            `json:"UFRInner"`
@@ -523,11 +538,20 @@ type FileDialogOptions struct {
 // App struct
 type App struct {
 	ctx context.Context
+
+	pythonMu          sync.Mutex
+	pythonVersions    map[string]string // --version output keyed by interpreter command
+	pythonInvocations []PythonInvocation
+
+	csvIndexes csvIndexCache    // row counts and seek offsets of large CSVs
+	fileCache  *outputFileCache // parsed output files, reused while unchanged on disk
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	return &App{
+		fileCache: newOutputFileCache(defaultFileCacheMaxEntries, defaultFileCacheMaxBytes),
+	}
 }
 
 // startup is called when the app starts. The context is saved
@@ -691,29 +715,42 @@ func (a *App) OpenFileDialog(fileOptions FileDialogOptions) (string, error) {
 	return filePath, nil
 }
 
+// ExecutePalm runs the pALM engine. path can be the launcher or the folder holding it,
+// the launcher for the current platform is resolved from there
 func (a *App) ExecutePalm(path string, configPath string, configName string) error {
+	// Create a function to send events to the frontend
+	sendEvent := func(name string, data string) {
+		runtime.EventsEmit(a.ctx, name, data)
+	}
 
-	// get the directory containing the executable
-	dir, err := filepath.Abs(filepath.Dir(path))
+	err := a.runPalm(path, configPath, configName, sendEvent)
 	if err != nil {
-		runtime.LogError(a.ctx, "Error getting directory: "+err.Error())
+		// Emit a completion event with the error message
+		sendEvent("commandCompleted", fmt.Sprintf("Error: %s", err.Error()))
 		return err
 	}
 
-	// create the command
-	cmd := exec.Command(path, "run", "--config", configPath, "--configname", configName)
+	// Emit a completion event
+	sendEvent("commandCompleted", "Success")
+	return nil
+}
 
-	// set the working directory
-	cmd.Dir = dir
+// runPalm runs the engine and waits for it, passing each stdout/stderr line to output
+// with "stdout" or "stderr" as stream
+func (a *App) runPalm(path string, configPath string, configName string, output func(stream string, line string)) error {
 
-	// --- Start: Platform-specific code to hide window ---
-	if stdruntime.GOOS == "windows" {
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			HideWindow:    true,       // This is the crucial flag for Windows
-			CreationFlags: 0x08000000, // CREATE_NO_WINDOW - Alternative/additional flag
-		}
+	// find the launcher (pALMLauncher.exe, pALMLauncher or dotnet pALMLauncher.dll)
+	launcher, err := resolvePalmLauncher(path)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error resolving pALM launcher: "+err.Error())
+		return err
 	}
-	// --- End: Platform-specific code ---
+	runtime.LogInfof(a.ctx, "Running pALM with %s launcher: %s", launcher.Kind, launcher.Path)
+
+	configPath = rebaseConfigPath(configPath, path, launcher.Dir)
+
+	// create the command, the working directory is the launcher folder
+	cmd := launcher.command("run", "--config", configPath, "--configname", configName)
 
 	// Create pipes for stdout and stderr
 	stdout, err := cmd.StdoutPipe()
@@ -734,45 +771,29 @@ func (a *App) ExecutePalm(path string, configPath string, configName string) err
 		return err
 	}
 
-	// Create a function to send events to the frontend
-	sendEvent := func(name string, data string) {
-		runtime.EventsEmit(a.ctx, name, data)
-	}
-
-	// Create a scanner to read stdout line by line
-	go func() {
-		scanner := bufio.NewScanner(stdout)
+	// read stdout and stderr line by line until both pipes are closed
+	var wg sync.WaitGroup
+	readLines := func(stream string, pipe io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(pipe)
 		for scanner.Scan() {
-			line := scanner.Text()
-			sendEvent("stdout", line) // Emit stdout event
+			output(stream, scanner.Text())
 		}
 		if err := scanner.Err(); err != nil {
-			runtime.LogError(a.ctx, "Error reading stdout: "+err.Error())
+			runtime.LogError(a.ctx, "Error reading "+stream+": "+err.Error())
 		}
-	}()
-
-	// Create a scanner to read stderr line by line
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			line := scanner.Text()
-			sendEvent("stderr", line) // Emit stderr event
-		}
-		if err := scanner.Err(); err != nil {
-			runtime.LogError(a.ctx, "Error reading stderr: "+err.Error())
-		}
-	}()
+	}
+	wg.Add(2)
+	go readLines("stdout", stdout)
+	go readLines("stderr", stderr)
+	wg.Wait()
 
 	// Wait for the command to complete
 	if err := cmd.Wait(); err != nil {
 		runtime.LogError(a.ctx, "Error waiting for program: "+err.Error())
-		// Emit a completion event with the error message
-		sendEvent("commandCompleted", fmt.Sprintf("Error: %s", err.Error()))
 		return err
 	}
 
-	// Emit a completion event
-	sendEvent("commandCompleted", "Success")
 	return nil
 }
 
@@ -811,122 +832,37 @@ type CSVFile struct {
 	Data [][]string // Parsed CSV data
 }
 
-// only reading CSV files for now
+// ReadFiles reads the CSV output files of a folder whose name contains
+// filterString. Files are parsed in parallel and cached until they change on disk.
+// ReadFilesMatching takes glob/regex patterns, a depth limit, sorting and a file cap
 func (a *App) ReadFiles(path string, filterString string, walkSubdirectories bool) ([]CSVFile, error) {
-
-	// CSVData represents the parsed CSV data as a slice of string slices.
-	var result []CSVFile
+	options := ReadFilesOptions{Filter: filterString}
 
 	// if walkSubDirectories, walk through all files and subdir recursively
 	// if false, then just read the main directory
 	if walkSubdirectories {
-		err := filepath.WalkDir(path, func(fPath string, d os.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-
-			// Skip the root directory itself
-			if fPath == "." {
-				return nil
-			}
-
-			fileInfo, err := processFile(fPath, d.Name(), filterString)
-			if err != nil {
-				runtime.LogErrorf(a.ctx, "Error processing file %s: %v\n", fPath, err)
-				return err
-			} else if fileInfo != nil {
-				result = append(result, *fileInfo)
-			}
-
-			return nil
-		})
-
-		if err != nil {
-			return nil, err
-		}
-
-		return result, nil
-	} else {
-
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			runtime.LogError(a.ctx, "Error reading directory"+err.Error())
-			return nil, err
-		}
-
-		for _, entry := range entries {
-			if entry.IsDir() {
-				continue // skip
-			}
-
-			fullPath := filepath.Join(path, entry.Name())
-
-			fileInfo, err := processFile(fullPath, entry.Name(), filterString)
-			if err != nil {
-				runtime.LogErrorf(a.ctx, "Error processing file %s: %v\n", fullPath, err)
-				continue
-			} else if fileInfo != nil {
-				result = append(result, *fileInfo)
-			}
-		}
-	}
-	return result, nil
-}
-
-func processFile(fPath string, fName string, filterString string) (*CSVFile, error) {
-	if !strings.HasSuffix(strings.ToLower(fName), ".csv") {
-		return nil, nil // not a csv, just skip
+		options.MaxDepth = -1
 	}
 
-	applyFilter := filterString != ""
-	passesFilter := true // set to true as default
-
-	if applyFilter {
-		passesFilter = strings.Contains(fName, filterString)
-	}
-
-	if !passesFilter {
-		return nil, nil // not error, just filters out
-	}
-
-	// Parse the CSV file
-	csvData, err := parseCSVFile(fPath)
-	if err != nil {
-		return nil, err // Skip to the next file
-	}
-
-	// Create a CSVFile struct and append it to the result
-	csvFile := CSVFile{
-		Path: fPath,
-		Name: fName,
-		Data: csvData,
-	}
-
-	return &csvFile, nil
+	// a bad file fails the whole walk, a flat read skips it
+	return a.readFiles(path, options, walkSubdirectories)
 }
 
-// Helper function to parse a single CSV file
+// Helper function to parse a single CSV file, the delimiter is detected from the first line.
+// trailing delimiters on the header are ignored and data rows are fitted to the header width
 func parseCSVFile(filename string) ([][]string, error) {
-	file, err := os.Open(filename)
+	delimiter, err := detectFileDelimiter(filename)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	var buffer bytes.Buffer
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmedLine := strings.TrimRight(line, ",")
-		buffer.WriteString(trimmedLine + "\n")
-	}
 
-	if err := scanner.Err(); err != nil {
+	file, err := openTextFile(filename)
+	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
-	reader := csv.NewReader(bytes.NewReader(buffer.Bytes()))
-	reader.FieldsPerRecord = -1 // Allow variable number of fields per record
+	reader := newDelimitedReader(file, delimiter)
 
 	var data [][]string
 
@@ -935,9 +871,9 @@ func parseCSVFile(filename string) ([][]string, error) {
 		if err == io.EOF {
 			return data, nil // Empty file
 		}
-		return nil, err
+		return nil, csvLineError(filename, err)
 	}
-	// expectedColumns := len(header)
+	header = trimTrailingEmpty(header)
 	data = append(data, header)
 
 	for {
@@ -946,11 +882,10 @@ func parseCSVFile(filename string) ([][]string, error) {
 			break
 		}
 		if err != nil {
-			fmt.Println("error:", err)
-			return nil, err
+			return nil, csvLineError(filename, err)
 		}
 
-		data = append(data, record)
+		data = append(data, fitRecord(record, len(header)))
 	}
 
 	return data, nil
@@ -958,29 +893,40 @@ func parseCSVFile(filename string) ([][]string, error) {
 
 // RunPythonScript runs a Python script with the given parameters and returns its output.
 func (a *App) ExecutePythonScript(scriptPath string, params []string) (string, error) {
-	cmdParams := append([]string{scriptPath}, params...)
-	cmd := exec.Command("python", cmdParams...)
+	config, err := a.ReadUIConfig()
+	if err != nil {
+		config = nil // no overrides, interpreter is discovered on PATH
+	}
+
+	interpreter, err := a.resolvePythonInterpreter(config, scriptPath)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error resolving python interpreter: "+err.Error())
+		return "", err
+	}
+
+	cmdParams := append(append([]string{}, interpreter.Args...), scriptPath)
+	cmdParams = append(cmdParams, params...)
+	cmd := exec.Command(interpreter.Path, cmdParams...)
 
 	// Set the working directory
 	cmd.Dir = filepath.Dir(scriptPath) // Use the directory containing the script
 
-	// hide terminal window from popping up in production (windows only)
-	if stdruntime.GOOS == "windows" {
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			HideWindow:    true,       // This is the crucial flag for Windows
-			CreationFlags: 0x08000000, // CREATE_NO_WINDOW - Alternative/additional flag
-		}
-	}
+	// hide terminal window from popping up in production (windows), own process group elsewhere
+	setChildProcessAttributes(cmd)
 
+	started := time.Now()
 	output, err := cmd.Output()
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("error running script: %w, stderr: %s", err, string(exitError.Stderr))
+			err = fmt.Errorf("error running script: %w, stderr: %s", err, string(exitError.Stderr))
+		} else {
+			err = fmt.Errorf("error running script: %w", err)
 		}
-		return "", fmt.Errorf("error running script: %w", err)
 	}
-
-	fmt.Println(string(output))
+	a.recordPythonInvocation(newPythonInvocation(scriptPath, params, interpreter, started, err))
+	if err != nil {
+		return "", err
+	}
 
 	return string(output), nil
 }
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// most changed cells returned by CompareOutputs when CompareOptions.MaxRows is 0
+const defaultCompareMaxRows = 1000
+
+// CompareOptions controls how two output folders are aligned and compared
+type CompareOptions struct {
+	Files        ReadFilesOptions // files compared in both folders, as in ReadFilesMatching
+	RunNameA     string           // run name in runA's file names, replaced so files of both runs line up
+	RunNameB     string
+	KeyColumns   []string // columns identifying a row, the schema's keys (or the row order) when empty
+	AbsTolerance float64  // a cell is unchanged when |b - a| <= AbsTolerance
+	RelTolerance float64  // or when |b - a| / |a| <= RelTolerance
+	MaxRows      int      // most changed cells returned, 1000 when 0
+}
+
+// OutputComparison is the result of CompareOutputs
+type OutputComparison struct {
+	RunA           string
+	RunB           string
+	Files          []FileComparison
+	OnlyInA        []string // files without a counterpart in runB
+	OnlyInB        []string
+	Metrics        []MetricDiff // one per compared column of each file
+	ChangedMetrics int          // metrics with at least one cell outside the tolerances
+	Rows           []CellDiff   // changed cells, at most MaxRows
+	Truncated      bool         // more cells changed than returned in Rows
+}
+
+// FileComparison summarises the comparison of one pair of files
+type FileComparison struct {
+	Pattern      string // file name both files share once run names are replaced with "*"
+	PathA        string
+	PathB        string
+	KeyColumns   []string // empty when rows were aligned by their order
+	RowsA        int
+	RowsB        int
+	RowsOnlyA    int // rows whose key isn't in the other file
+	RowsOnlyB    int
+	ColumnsOnlyA []string
+	ColumnsOnlyB []string
+	ChangedCells int
+}
+
+// MetricDiff summarises the differences of one column of a file
+type MetricDiff struct {
+	File          string // file pattern
+	Column        string
+	CellsCompared int
+	CellsChanged  int
+	SumA          float64
+	SumB          float64
+	MaxAbsDiff    float64
+	MaxRelDiff    *float64 // nil when undefined, e.g. every changed cell was 0 in runA
+	MaxDiffKey    string   // row key of the largest absolute difference
+}
+
+// CellDiff is a cell outside the tolerances. A, B, AbsDiff and RelDiff are nil when undefined
+// (blank cells, text columns, a zero in runA)
+type CellDiff struct {
+	File    string
+	Key     string
+	Column  string
+	A       *float64
+	B       *float64
+	TextA   string // set for text columns
+	TextB   string
+	AbsDiff *float64
+	RelDiff *float64
+}
+
+// CompareOutputs diffs the output folders of two runs: files are aligned by name (with run
+// names masked), rows by their key columns, and every numeric cell gets an absolute and a
+// relative difference checked against the tolerances
+func (a *App) CompareOutputs(runA string, runB string, options CompareOptions) (*OutputComparison, error) {
+	if options.MaxRows <= 0 {
+		options.MaxRows = defaultCompareMaxRows
+	}
+
+	tablesA, err := a.readComparisonTables(runA, options.Files, options.RunNameA)
+	if err != nil {
+		return nil, fmt.Errorf("run A: %w", err)
+	}
+	tablesB, err := a.readComparisonTables(runB, options.Files, options.RunNameB)
+	if err != nil {
+		return nil, fmt.Errorf("run B: %w", err)
+	}
+
+	comparison := &OutputComparison{RunA: runA, RunB: runB, Rows: []CellDiff{}}
+
+	var patterns []string
+	for pattern := range tablesA {
+		if _, ok := tablesB[pattern]; ok {
+			patterns = append(patterns, pattern)
+		} else {
+			comparison.OnlyInA = append(comparison.OnlyInA, tablesA[pattern].Path)
+		}
+	}
+	for pattern, table := range tablesB {
+		if _, ok := tablesA[pattern]; !ok {
+			comparison.OnlyInB = append(comparison.OnlyInB, table.Path)
+		}
+	}
+	sortGroups(patterns)
+	sortGroups(comparison.OnlyInA)
+	sortGroups(comparison.OnlyInB)
+
+	for _, pattern := range patterns {
+		file, metrics := compareTables(pattern, tablesA[pattern], tablesB[pattern], options, comparison)
+		comparison.Files = append(comparison.Files, file)
+		comparison.Metrics = append(comparison.Metrics, metrics...)
+	}
+
+	for _, metric := range comparison.Metrics {
+		if metric.CellsChanged > 0 {
+			comparison.ChangedMetrics++
+		}
+	}
+
+	return comparison, nil
+}
+
+// readComparisonTables reads the selected files of a run, keyed by their path relative to the
+// run folder with the run name replaced by "*", see maskRunName
+func (a *App) readComparisonTables(folder string, selection ReadFilesOptions, runName string) (map[string]*OutputTable, error) {
+	candidates, err := selectFiles(folder, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]*OutputTable, len(candidates))
+	for i, parsed := range a.parseFiles(candidates) {
+		if parsed.err != nil {
+			return nil, fmt.Errorf("%s: %w", candidates[i].path, parsed.err)
+		}
+
+		rel, err := filepath.Rel(folder, candidates[i].path)
+		if err != nil {
+			return nil, err
+		}
+		pattern := maskRunName(filepath.ToSlash(rel), runName)
+
+		table := newOutputTable(*parsed.file)
+		tables[pattern] = &table
+	}
+	return tables, nil
+}
+
+// maskRunName replaces the run name with "*" where it is a whole token of the path, a run
+// named "a" leaves LiabilityOutput_a_Scenario_0.csv as LiabilityOutput_*_Scenario_0.csv
+func maskRunName(path string, runName string) string {
+	var masked strings.Builder
+	for {
+		i := runNameIndex(path, runName)
+		if i < 0 {
+			masked.WriteString(path)
+			return masked.String()
+		}
+		masked.WriteString(path[:i])
+		masked.WriteString("*")
+		path = path[i+len(runName):]
+	}
+}
+
+// comparisonKeys returns the key columns used to align two tables, nil to align by row order
+func comparisonKeys(tableA *OutputTable, tableB *OutputTable, options CompareOptions) []string {
+	candidates := options.KeyColumns
+	if len(candidates) == 0 {
+		candidates = tableA.KeyColumns
+	}
+
+	var keys []string
+	for _, key := range candidates {
+		if tableA.Column(key) != nil && tableB.Column(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// rowKeys builds the key of every row. repeated keys get an occurrence suffix so rows
+// such as several blocks in the same month still line up one to one
+func rowKeys(table *OutputTable, keys []string) []string {
+	result := make([]string, table.RowCount)
+	seen := make(map[string]int)
+
+	for row := 0; row < table.RowCount; row++ {
+		if len(keys) == 0 {
+			result[row] = fmt.Sprintf("row %d", row+1)
+			continue
+		}
+
+		parts := make([]string, len(keys))
+		for i, key := range keys {
+			parts[i] = key + "=" + cellString(table.Column(key), row)
+		}
+		key := strings.Join(parts, ", ")
+
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s #%d", key, seen[key])
+		}
+		result[row] = key
+	}
+	return result
+}
+
+func compareTables(pattern string, tableA *OutputTable, tableB *OutputTable, options CompareOptions, comparison *OutputComparison) (FileComparison, []MetricDiff) {
+	file := FileComparison{
+		Pattern: pattern,
+		PathA:   tableA.Path,
+		PathB:   tableB.Path,
+		RowsA:   tableA.RowCount,
+		RowsB:   tableB.RowCount,
+	}
+	file.KeyColumns = comparisonKeys(tableA, tableB, options)
+
+	isKey := make(map[string]bool, len(file.KeyColumns))
+	for _, key := range file.KeyColumns {
+		isKey[strings.ToLower(key)] = true
+	}
+
+	for _, column := range tableB.Columns {
+		if tableA.Column(column.Name) == nil {
+			file.ColumnsOnlyB = append(file.ColumnsOnlyB, column.Name)
+		}
+	}
+
+	keysA := rowKeys(tableA, file.KeyColumns)
+	keysB := rowKeys(tableB, file.KeyColumns)
+	rowOfB := make(map[string]int, len(keysB))
+	for row, key := range keysB {
+		rowOfB[key] = row
+	}
+
+	matched := 0
+	pairs := make([][2]int, 0, len(keysA))
+	for rowA, key := range keysA {
+		if rowB, ok := rowOfB[key]; ok {
+			pairs = append(pairs, [2]int{rowA, rowB})
+			matched++
+		}
+	}
+	file.RowsOnlyA = tableA.RowCount - matched
+	file.RowsOnlyB = tableB.RowCount - matched
+
+	var metrics []MetricDiff
+	for i := range tableA.Columns {
+		columnA := &tableA.Columns[i]
+		if isKey[strings.ToLower(columnA.Name)] {
+			continue
+		}
+		columnB := tableB.Column(columnA.Name)
+		if columnB == nil {
+			file.ColumnsOnlyA = append(file.ColumnsOnlyA, columnA.Name)
+			continue
+		}
+
+		metric := MetricDiff{File: pattern, Column: columnA.Name}
+		numeric := columnA.Type != ColumnTypeString && columnB.Type != ColumnTypeString
+
+		for _, pair := range pairs {
+			metric.CellsCompared++
+			key := keysA[pair[0]]
+
+			if !numeric {
+				textA, textB := cellString(columnA, pair[0]), cellString(columnB, pair[1])
+				if textA != textB {
+					metric.CellsChanged++
+					addCellDiff(comparison, CellDiff{File: pattern, Key: key, Column: columnA.Name, TextA: textA, TextB: textB}, options)
+				}
+				continue
+			}
+
+			valueA, valueB := cellNumber(columnA, pair[0]), cellNumber(columnB, pair[1])
+			if !math.IsNaN(valueA) {
+				metric.SumA += valueA
+			}
+			if !math.IsNaN(valueB) {
+				metric.SumB += valueB
+			}
+
+			diff := CellDiff{File: pattern, Key: key, Column: columnA.Name, A: finiteOrNil(valueA), B: finiteOrNil(valueB)}
+			if withinTolerance(valueA, valueB, options, &diff) {
+				continue
+			}
+
+			metric.CellsChanged++
+			if diff.AbsDiff != nil && math.Abs(*diff.AbsDiff) > metric.MaxAbsDiff {
+				metric.MaxAbsDiff = math.Abs(*diff.AbsDiff)
+				metric.MaxDiffKey = key
+			}
+			if diff.RelDiff != nil && (metric.MaxRelDiff == nil || math.Abs(*diff.RelDiff) > *metric.MaxRelDiff) {
+				rel := math.Abs(*diff.RelDiff)
+				metric.MaxRelDiff = &rel
+			}
+			addCellDiff(comparison, diff, options)
+		}
+
+		file.ChangedCells += metric.CellsChanged
+		metrics = append(metrics, metric)
+	}
+
+	return file, metrics
+}
+
+// withinTolerance fills the differences of diff and reports whether the cell is unchanged.
+// two blanks are equal, a blank against a number is always a change
+func withinTolerance(valueA float64, valueB float64, options CompareOptions, diff *CellDiff) bool {
+	blankA, blankB := math.IsNaN(valueA), math.IsNaN(valueB)
+	if blankA || blankB {
+		return blankA && blankB
+	}
+	if valueA == valueB {
+		return true
+	}
+
+	abs := valueB - valueA
+	diff.AbsDiff = finiteOrNil(abs)
+	if valueA != 0 {
+		diff.RelDiff = finiteOrNil(abs / math.Abs(valueA))
+	}
+
+	if math.Abs(abs) <= options.AbsTolerance {
+		return true
+	}
+	return diff.RelDiff != nil && math.Abs(*diff.RelDiff) <= options.RelTolerance
+}
+
+func addCellDiff(comparison *OutputComparison, diff CellDiff, options CompareOptions) {
+	if len(comparison.Rows) >= options.MaxRows {
+		comparison.Truncated = true
+		return
+	}
+	comparison.Rows = append(comparison.Rows, diff)
+}
+
+// finiteOrNil returns nil for NaN and infinities, which JSON can't carry
+func finiteOrNil(value float64) *float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestMaskRunName(t *testing.T) {
+	tests := []struct {
+		path    string
+		runName string
+		want    string
+	}{
+		{"my_run_LiabilityOutput_Scenario_0.csv", "my_run", "*_LiabilityOutput_Scenario_0.csv"},
+		{"DebugInfo_Scenario_my_run_0.csv", "my_run", "DebugInfo_Scenario_*_0.csv"},
+		{"my_run/Parsed_Output.csv", "my_run", "*/Parsed_Output.csv"},
+		{"my_run2_LiabilityOutput_Scenario_0.csv", "my_run", "my_run2_LiabilityOutput_Scenario_0.csv"},
+		// a short run name only where it is a token
+		{"LiabilityOutput_a_Scenario_0.csv", "a", "LiabilityOutput_*_Scenario_0.csv"},
+		{"a_Parsed_Data.csv", "a", "*_Parsed_Data.csv"},
+		{"a_a.csv", "a", "*_*.csv"},
+		{"Parsed_Output.csv", "", "Parsed_Output.csv"},
+	}
+	for _, test := range tests {
+		if got := maskRunName(test.path, test.runName); got != test.want {
+			t.Errorf("maskRunName(%q, %q) = %q, want %q", test.path, test.runName, got, test.want)
+		}
+	}
+}
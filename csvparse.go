@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+var (
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// textFile is a text file read as UTF-8: a UTF-8 byte order mark is dropped and UTF-16
+// files (as written by .NET) are decoded. files without a BOM are read as they are
+type textFile struct {
+	io.Reader
+	file *os.File
+}
+
+func (t *textFile) Close() error { return t.file.Close() }
+
+func openTextFile(path string) (*textFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &textFile{
+		Reader: transform.NewReader(file, unicode.BOMOverride(transform.Nop)),
+		file:   file,
+	}, nil
+}
+
+// isUTF16 reports whether a file starts with a UTF-16 byte order mark
+func isUTF16(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, 2)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, err
+	}
+	head = head[:n]
+	return bytes.Equal(head, utf16LEBOM) || bytes.Equal(head, utf16BEBOM), nil
+}
+
+// newDelimitedReader returns a csv reader for the delimited files of pALM: any number of
+// fields per record, quoted fields may span lines and lines can be of any length
+func newDelimitedReader(r io.Reader, delimiter rune) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1 // ragged rows are fitted to the header afterwards
+	return reader
+}
+
+// fitRecord fits a data row to the header width: short rows are padded with empty cells,
+// empty cells past the header (a trailing delimiter) are dropped. extra cells with a value
+// are kept
+func fitRecord(record []string, width int) []string {
+	if len(record) < width {
+		padded := make([]string, width)
+		copy(padded, record)
+		return padded
+	}
+
+	end := len(record)
+	for end > width && record[end-1] == "" {
+		end--
+	}
+	return record[:end]
+}
+
+// csvLineError adds the file, line and column to csv parse errors
+func csvLineError(path string, err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%s: line %d, column %d: %w", path, parseErr.Line, parseErr.Column, parseErr.Err)
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
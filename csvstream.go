@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// a byte offset is remembered every csvCheckpointEvery rows so pages deep
+// into a large file don't have to re-read it from the start
+const csvCheckpointEvery = 5000
+
+// CSVDescription is the header-only summary of a CSV, without its data
+type CSVDescription struct {
+	Path        string
+	Name        string
+	SizeBytes   int64
+	Headers     []string
+	ColumnCount int
+	RowCount    int // data rows, header excluded
+}
+
+// CSVPage is a window of rows from a CSV, optionally limited to some columns
+type CSVPage struct {
+	Path    string
+	Headers []string // headers of the returned columns, in the requested order
+	Offset  int
+	Rows    [][]string
+	HasMore bool // there are rows after this page
+}
+
+// csvIterator streams the data rows of a CSV one at a time, in the style of bufio.Scanner:
+//
+//	it, err := newCSVIterator(path)
+//	defer it.Close()
+//	for it.Next() { row := it.Row() }
+//	err = it.Err()
+type csvIterator struct {
+	file    *os.File
+	comma   rune
+	decoded bool // UTF-16 file read through a decoder, offsets don't map to the file so it can't seek
+	reader  *csv.Reader
+	header  []string
+	row     []string
+	rowNum  int   // index of the current data row
+	base    int64 // file offset the reader started at
+	err     error
+}
+
+func newCSVIterator(path string) (*csvIterator, error) {
+	comma, err := detectFileDelimiter(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := isUTF16(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	it := &csvIterator{file: file, comma: comma, decoded: decoded, rowNum: -1}
+	it.reset(0)
+
+	header, err := it.reader.Read()
+	if err != nil && err != io.EOF {
+		file.Close()
+		return nil, csvLineError(path, err)
+	}
+	it.header = trimTrailingEmpty(header)
+	if len(it.header) > 0 {
+		it.header[0] = strings.TrimPrefix(it.header[0], "\uFEFF") // UTF-8 byte order mark
+	}
+
+	return it, nil
+}
+
+func (it *csvIterator) reset(offset int64) {
+	it.base = offset
+	var r io.Reader = bufio.NewReaderSize(it.file, 1<<20)
+	if it.decoded {
+		r = transform.NewReader(r, unicode.BOMOverride(transform.Nop))
+	}
+	it.reader = newDelimitedReader(r, it.comma)
+}
+
+// seek jumps to a known row boundary, rowNum being the index of the next row read
+func (it *csvIterator) seek(offset int64, rowNum int) error {
+	if _, err := it.file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	it.reset(offset)
+	it.rowNum = rowNum - 1
+	return nil
+}
+
+// Next reads the next data row, returning false at the end of the file or on error
+func (it *csvIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	record, err := it.reader.Read()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.row = fitRecord(record, len(it.header))
+	it.rowNum++
+	return true
+}
+
+func (it *csvIterator) Header() []string { return it.header }
+
+func (it *csvIterator) Row() []string { return it.row }
+
+// RowNum is the index of the current data row, starting at 0
+func (it *csvIterator) RowNum() int { return it.rowNum }
+
+// offset is the file offset of the start of the next row
+func (it *csvIterator) offset() int64 { return it.base + it.reader.InputOffset() }
+
+func (it *csvIterator) Err() error { return it.err }
+
+func (it *csvIterator) Close() error { return it.file.Close() }
+
+// trimTrailingEmpty drops empty trailing fields, the header of a file whose lines end
+// with a delimiter
+func trimTrailingEmpty(record []string) []string {
+	end := len(record)
+	for end > 0 && record[end-1] == "" {
+		end--
+	}
+	return record[:end]
+}
+
+// csvRowIndex remembers row counts and checkpoint offsets of files already scanned
+type csvRowIndex struct {
+	size        int64
+	modTime     time.Time
+	rowCount    int
+	checkpoints []int64 // checkpoints[i] is the file offset of data row i*csvCheckpointEvery
+}
+
+type csvIndexCache struct {
+	mu      sync.Mutex
+	indexes map[string]*csvRowIndex
+}
+
+func (c *csvIndexCache) get(path string, info os.FileInfo) *csvRowIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index, ok := c.indexes[path]
+	if !ok || index.size != info.Size() || !index.modTime.Equal(info.ModTime()) {
+		return nil
+	}
+	return index
+}
+
+func (c *csvIndexCache) put(path string, index *csvRowIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.indexes == nil {
+		c.indexes = make(map[string]*csvRowIndex)
+	}
+	c.indexes[path] = index
+}
+
+// DescribeCSV returns the headers and row count of a CSV without sending its data
+func (a *App) DescribeCSV(path string) (*CSVDescription, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	index, header, err := a.indexCSV(path, info)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error describing csv "+path+": "+err.Error())
+		return nil, err
+	}
+
+	return &CSVDescription{
+		Path:        path,
+		Name:        filepath.Base(path),
+		SizeBytes:   info.Size(),
+		Headers:     header,
+		ColumnCount: len(header),
+		RowCount:    index.rowCount,
+	}, nil
+}
+
+// indexCSV scans the whole file once, recording its row count and checkpoints
+func (a *App) indexCSV(path string, info os.FileInfo) (*csvRowIndex, []string, error) {
+	it, err := newCSVIterator(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+
+	if index := a.csvIndexes.get(path, info); index != nil {
+		return index, it.Header(), nil
+	}
+
+	index := &csvRowIndex{size: info.Size(), modTime: info.ModTime()}
+	index.checkpoints = append(index.checkpoints, it.offset())
+	for it.Next() {
+		index.rowCount++
+		if index.rowCount%csvCheckpointEvery == 0 && !it.decoded {
+			index.checkpoints = append(index.checkpoints, it.offset())
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, nil, fmt.Errorf("row %d: %w", index.rowCount+1, err)
+	}
+
+	a.csvIndexes.put(path, index)
+	return index, it.Header(), nil
+}
+
+// ReadCSVPage returns up to limit data rows starting at offset (0 being the first row after
+// the header). columns limits and orders the returned columns, all columns when empty
+func (a *App) ReadCSVPage(path string, offset int, limit int, columns []string) (*CSVPage, error) {
+	if offset < 0 || limit < 0 {
+		return nil, fmt.Errorf("invalid page offset %d / limit %d", offset, limit)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := newCSVIterator(path)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	projection, err := projectColumns(it.Header(), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CSVPage{Path: path, Offset: offset, Rows: [][]string{}}
+	for _, col := range projection {
+		page.Headers = append(page.Headers, it.Header()[col])
+	}
+
+	// jump to the closest checkpoint when the file was indexed by DescribeCSV
+	if index := a.csvIndexes.get(path, info); index != nil {
+		checkpoint := offset / csvCheckpointEvery
+		if checkpoint >= len(index.checkpoints) {
+			checkpoint = len(index.checkpoints) - 1
+		}
+		if checkpoint > 0 {
+			if err := it.seek(index.checkpoints[checkpoint], checkpoint*csvCheckpointEvery); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for it.Next() {
+		if it.RowNum() < offset {
+			continue
+		}
+		if len(page.Rows) == limit {
+			page.HasMore = true
+			break
+		}
+		page.Rows = append(page.Rows, projectRow(it.Row(), projection))
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("row %d: %w", it.RowNum()+2, err)
+	}
+
+	return page, nil
+}
+
+// projectColumns maps column names to their index in header, every column when names is empty
+func projectColumns(header []string, names []string) ([]int, error) {
+	if len(names) == 0 {
+		projection := make([]int, len(header))
+		for i := range header {
+			projection[i] = i
+		}
+		return projection, nil
+	}
+
+	var projection []int
+	var missing []string
+	for _, name := range names {
+		found := -1
+		for i, h := range header {
+			if strings.TrimSpace(h) == strings.TrimSpace(name) {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			missing = append(missing, name)
+			continue
+		}
+		projection = append(projection, found)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unknown columns: %s", strings.Join(missing, ", "))
+	}
+	return projection, nil
+}
+
+func projectRow(row []string, projection []int) []string {
+	projected := make([]string, len(projection))
+	for i, col := range projection {
+		if col < len(row) {
+			projected[i] = row[col]
+		}
+	}
+	return projected
+}
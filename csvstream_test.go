@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// writeTestCSV writes rows data rows of Row,Text,Value. every 997th text is a quoted field
+// spanning two lines, so row and line numbers drift apart across the checkpoints
+func writeTestCSV(t *testing.T, rows int) (string, [][]string) {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("Row,Text,Value\n")
+	expected := make([][]string, rows)
+	for i := 0; i < rows; i++ {
+		text := fmt.Sprintf("row %d", i)
+		cell := text
+		if i%997 == 0 || i == csvCheckpointEvery-1 || i == csvCheckpointEvery {
+			text = fmt.Sprintf("row %d, \"quoted\"\nsecond line", i)
+			cell = `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		}
+		fmt.Fprintf(&b, "%d,%s,%d.5\n", i, cell, i)
+		expected[i] = []string{fmt.Sprint(i), text, fmt.Sprintf("%d.5", i)}
+	}
+	path := filepath.Join(t.TempDir(), "rows.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, expected
+}
+
+func TestReadCSVPageAcrossCheckpoints(t *testing.T) {
+	path, expected := writeTestCSV(t, 2*csvCheckpointEvery+500)
+
+	pages := []struct {
+		offset int
+		limit  int
+	}{
+		{0, 10},
+		{csvCheckpointEvery - 5, 10},   // crosses the first checkpoint
+		{csvCheckpointEvery, 3},        // starts on it
+		{2*csvCheckpointEvery - 1, 20}, // crosses the second
+		{2*csvCheckpointEvery + 490, 50},
+		{3 * csvCheckpointEvery, 10}, // past the end
+	}
+
+	indexed := NewApp()
+	description, err := indexed.DescribeCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if description.RowCount != len(expected) {
+		t.Fatalf("row count %d, want %d", description.RowCount, len(expected))
+	}
+	if index := indexed.csvIndexes.get(path, mustStat(t, path)); index == nil || len(index.checkpoints) != 3 {
+		t.Fatalf("index %+v, want 3 checkpoints", index)
+	}
+
+	for _, app := range []*App{NewApp(), indexed} {
+		for _, p := range pages {
+			page, err := app.ReadCSVPage(path, p.offset, p.limit, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			end := p.offset + p.limit
+			if end > len(expected) {
+				end = len(expected)
+			}
+			want := [][]string{}
+			if p.offset < len(expected) {
+				want = expected[p.offset:end]
+			}
+			if !reflect.DeepEqual(page.Rows, want) {
+				t.Errorf("offset %d: got rows %q, want %q", p.offset, page.Rows, want)
+			}
+			if page.HasMore != (end < len(expected)) {
+				t.Errorf("offset %d: HasMore %v", p.offset, page.HasMore)
+			}
+		}
+	}
+}
+
+func TestReadCSVPageColumns(t *testing.T) {
+	path, expected := writeTestCSV(t, 20)
+	page, err := NewApp().ReadCSVPage(path, 0, 2, []string{"Value", "Text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{expected[0][2], expected[0][1]}, {expected[1][2], expected[1][1]}}
+	if !reflect.DeepEqual(page.Headers, []string{"Value", "Text"}) || !reflect.DeepEqual(page.Rows, want) {
+		t.Errorf("got %q %q, want %q", page.Headers, page.Rows, want)
+	}
+}
+
+func TestReadCSVPageUTF16(t *testing.T) {
+	rows := csvCheckpointEvery + 10
+	var b strings.Builder
+	b.WriteString("Month;Rate\r\n")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&b, "%d;%d,5\r\n", i, i)
+	}
+	encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "utf16.csv")
+	if err := os.WriteFile(path, []byte(encoded), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp()
+	description, err := app.DescribeCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if description.RowCount != rows || !reflect.DeepEqual(description.Headers, []string{"Month", "Rate"}) {
+		t.Fatalf("got %d rows %q, want %d rows [Month Rate]", description.RowCount, description.Headers, rows)
+	}
+
+	page, err := app.ReadCSVPage(path, csvCheckpointEvery-1, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{fmt.Sprint(csvCheckpointEvery - 1), fmt.Sprintf("%d,5", csvCheckpointEvery-1)},
+		{fmt.Sprint(csvCheckpointEvery), fmt.Sprintf("%d,5", csvCheckpointEvery)},
+	}
+	if !reflect.DeepEqual(page.Rows, want) || !page.HasMore {
+		t.Errorf("got %q (more %v), want %q", page.Rows, page.HasMore, want)
+	}
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
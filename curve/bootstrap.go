@@ -0,0 +1,97 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+)
+
+// bootstrap turns the par yields in c.rates into continuous zero rates, one tenor at a time.
+// a par bond pays its yield Frequency times a year (the first period being a stub) and prices
+// at par on the curve bootstrapped so far. a cubic spline moves between earlier nodes as nodes
+// are added, so its tenors are solved again on the whole curve until every bond is at par
+func (c *Curve) bootstrap() error {
+	frequency := c.options.Frequency
+	if frequency <= 0 {
+		frequency = 1
+	}
+	period := 1 / float64(frequency)
+
+	par := c.rates
+	c.rates = make([]float64, 0, len(par))
+	tenors := c.tenors
+
+	for i := range tenors {
+		c.tenors = tenors[:i+1]
+		c.rates = append(c.rates, 0)
+		if err := c.solvePar(i, par[i], period); err != nil {
+			return err
+		}
+	}
+	c.tenors = tenors
+	c.fit()
+
+	if c.spline == nil {
+		return nil
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		change := 0.0
+		for i := range tenors {
+			previous := c.rates[i]
+			if err := c.solvePar(i, par[i], period); err != nil {
+				return err
+			}
+			change = math.Max(change, math.Abs(c.rates[i]-previous))
+		}
+		if change < 1e-13 {
+			break
+		}
+	}
+	c.fit()
+	return nil
+}
+
+// solvePar sets the zero rate of tenor i so the par bond maturing there prices at par on the
+// curve's current tenors
+func (c *Curve) solvePar(i int, coupon float64, period float64) error {
+	maturity := c.tenors[i]
+
+	// a single payment: (1 + coupon * T) P(T) = 1
+	if maturity <= period+1e-9 {
+		c.rates[i] = math.Log1p(coupon*maturity) / maturity
+		c.fit()
+		return nil
+	}
+
+	var dates []float64
+	for t := maturity; t > 1e-9; t -= period {
+		dates = append([]float64{t}, dates...)
+	}
+
+	// the bond's price less par falls as the zero rate at maturity rises: bisect
+	priceGap := func(rate float64) float64 {
+		c.rates[i] = rate
+		c.fit()
+		value, previous := 0.0, 0.0
+		for _, t := range dates {
+			value += coupon * (t - previous) * c.Discount(t)
+			previous = t
+		}
+		return value + c.Discount(maturity) - 1
+	}
+
+	lo, hi := -0.5, 1.0
+	if priceGap(lo) < 0 || priceGap(hi) > 0 {
+		return fmt.Errorf("can't bootstrap the par rate %g at %g years", coupon, maturity)
+	}
+	for n := 0; n < 200 && hi-lo > 1e-14; n++ {
+		mid := (lo + hi) / 2
+		if priceGap(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	c.rates[i] = (lo + hi) / 2
+	c.fit()
+	return nil
+}
@@ -0,0 +1,369 @@
+// Package curve builds zero and forward curves from the tenor to rate maps of the ESG
+// scenario configs: bootstrapping from zero or par rates, interpolation between tenors,
+// extrapolation to an ultimate forward rate and conversion between rate conventions.
+//
+// rates are decimals (0.05 for 5%); callers holding percent scale them first
+package curve
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Convention is how a rate compounds
+type Convention string
+
+const (
+	Annual     Convention = "annual"
+	Continuous Convention = "continuous"
+)
+
+// ParseConvention reads a RateConvention, anything starting with "cont" is continuous and
+// everything else annual
+func ParseConvention(s string) Convention {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(s)), "cont") {
+		return Continuous
+	}
+	return Annual
+}
+
+// ToContinuous converts a rate in convention to its continuously compounded equivalent
+func ToContinuous(rate float64, convention Convention) float64 {
+	if convention == Annual {
+		return math.Log1p(rate)
+	}
+	return rate
+}
+
+// FromContinuous converts a continuously compounded rate to convention
+func FromContinuous(rate float64, convention Convention) float64 {
+	if convention == Annual {
+		return math.Expm1(rate)
+	}
+	return rate
+}
+
+// Interpolation is how rates between two tenors are found
+type Interpolation string
+
+const (
+	InterpolateLinear      Interpolation = "linear"      // linear in zero rates
+	InterpolateCubic       Interpolation = "cubic"       // natural cubic spline through the zero rates
+	InterpolateFlatForward Interpolation = "flatforward" // constant forward between tenors
+)
+
+// Extrapolation is how rates beyond the longest tenor are found
+type Extrapolation string
+
+const (
+	ExtrapolateFlat        Extrapolation = "flat"        // the last zero rate
+	ExtrapolateFlatForward Extrapolation = "flatforward" // the last forward, then the UFR from UFRStart
+	ExtrapolateSmithWilson Extrapolation = "smithwilson" // Smith-Wilson converging to the UFR
+)
+
+// ParseInterpolation reads an interpolation name ignoring case, spaces, dashes and underscores.
+// linear when empty
+func ParseInterpolation(s string) (Interpolation, error) {
+	switch Interpolation(normaliseName(s)) {
+	case "", InterpolateLinear:
+		return InterpolateLinear, nil
+	case InterpolateCubic, "cubicspline", "spline":
+		return InterpolateCubic, nil
+	case InterpolateFlatForward:
+		return InterpolateFlatForward, nil
+	}
+	return "", fmt.Errorf("unknown interpolation %q", s)
+}
+
+// ParseExtrapolation reads an extrapolation name as ParseInterpolation does. flat when empty
+func ParseExtrapolation(s string) (Extrapolation, error) {
+	switch Extrapolation(normaliseName(s)) {
+	case "", ExtrapolateFlat:
+		return ExtrapolateFlat, nil
+	case ExtrapolateFlatForward:
+		return ExtrapolateFlatForward, nil
+	case ExtrapolateSmithWilson, "sw":
+		return ExtrapolateSmithWilson, nil
+	}
+	return "", fmt.Errorf("unknown extrapolation %q", s)
+}
+
+func normaliseName(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// InputType is what the rates given to a curve are
+type InputType string
+
+const (
+	ZeroRates InputType = "zero" // spot (zero coupon) rates
+	ParRates  InputType = "par"  // par yields, bootstrapped to zero rates
+)
+
+// Options describe the rates a curve is built from and how it interpolates and extrapolates
+type Options struct {
+	Convention    Convention // of the zero rates given and returned
+	Input         InputType  // zero when empty
+	Frequency     int        // coupons a year of par rates, 1 when 0
+	Interpolation Interpolation
+	Extrapolation Extrapolation
+	UFR           float64 // ultimate forward rate in Convention, none when 0
+	UFRStart      float64 // years: where flat forward switches to the UFR and Smith-Wilson converges
+	Alpha         float64 // Smith-Wilson convergence speed, calibrated to UFRStart when 0
+}
+
+// Curve is a zero curve through rates at a set of tenors
+type Curve struct {
+	options Options
+	tenors  []float64 // years, ascending
+	rates   []float64 // continuously compounded zero rates
+	spline  []float64 // second derivatives of the cubic spline
+	ufr     float64   // continuous UFR, NaN when unset
+	sw      *smithWilson
+}
+
+// Node is a tenor of the curve with its zero rate in the curve's convention
+type Node struct {
+	Years float64
+	Rate  float64
+}
+
+// Point is the curve at one month
+type Point struct {
+	Month    int
+	Years    float64
+	Zero     float64 // zero rate to Years
+	Forward  float64 // forward rate over the following month
+	Discount float64
+}
+
+// FromTenors builds a curve from a tenor label to rate map such as sofrSpotRate. tenors of 0 are ignored
+func FromTenors(rates map[string]float64, options Options) (*Curve, error) {
+	tenors := make([]float64, 0, len(rates))
+	values := make([]float64, 0, len(rates))
+	for label, rate := range rates {
+		years, err := ParseTenor(label)
+		if err != nil {
+			return nil, err
+		}
+		if years == 0 {
+			continue
+		}
+		tenors = append(tenors, years)
+		values = append(values, rate)
+	}
+	return New(tenors, values, options)
+}
+
+// New builds a curve from rates at tenors in years
+func New(tenors []float64, rates []float64, options Options) (*Curve, error) {
+	if len(tenors) != len(rates) {
+		return nil, fmt.Errorf("%d tenors for %d rates", len(tenors), len(rates))
+	}
+	if len(tenors) == 0 {
+		return nil, fmt.Errorf("no tenor longer than 0")
+	}
+	if options.Convention == "" {
+		options.Convention = Annual
+	}
+	if options.Interpolation == "" {
+		options.Interpolation = InterpolateLinear
+	}
+	if options.Extrapolation == "" {
+		options.Extrapolation = ExtrapolateFlat
+	}
+
+	order := make([]int, len(tenors))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return tenors[order[i]] < tenors[order[j]] })
+
+	c := &Curve{options: options, ufr: math.NaN()}
+	for n, i := range order {
+		if tenors[i] <= 0 || math.IsNaN(rates[i]) || math.IsInf(rates[i], 0) {
+			return nil, fmt.Errorf("invalid rate %g at tenor %g", rates[i], tenors[i])
+		}
+		if n > 0 && tenors[i] == c.tenors[n-1] {
+			return nil, fmt.Errorf("tenor %g given twice", tenors[i])
+		}
+		c.tenors = append(c.tenors, tenors[i])
+		c.rates = append(c.rates, rates[i])
+	}
+	if options.UFR != 0 {
+		c.ufr = ToContinuous(options.UFR, options.Convention)
+	}
+
+	if options.Input == ParRates {
+		if err := c.bootstrap(); err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range c.rates {
+			c.rates[i] = ToContinuous(c.rates[i], options.Convention)
+		}
+		c.fit()
+	}
+
+	if options.Extrapolation == ExtrapolateSmithWilson {
+		if math.IsNaN(c.ufr) {
+			return nil, fmt.Errorf("Smith-Wilson extrapolation needs a UFR")
+		}
+		sw, err := fitSmithWilson(c.tenors, c.rates, c.ufr, options.UFRStart, options.Alpha)
+		if err != nil {
+			return nil, err
+		}
+		c.sw = sw
+	}
+	return c, nil
+}
+
+// fit prepares the interpolation once the zero rates are known
+func (c *Curve) fit() {
+	c.spline = nil
+	if c.options.Interpolation == InterpolateCubic && len(c.tenors) > 2 {
+		c.spline = naturalSpline(c.tenors, c.rates)
+	}
+}
+
+// Nodes returns the curve's tenors with their zero rates
+func (c *Curve) Nodes() []Node {
+	nodes := make([]Node, len(c.tenors))
+	for i := range c.tenors {
+		nodes[i] = Node{Years: c.tenors[i], Rate: FromContinuous(c.rates[i], c.options.Convention)}
+	}
+	return nodes
+}
+
+// Alpha is the Smith-Wilson convergence speed used, 0 for other extrapolations
+func (c *Curve) Alpha() float64 {
+	if c.sw == nil {
+		return 0
+	}
+	return c.sw.alpha
+}
+
+// Zero is the zero rate to t years
+func (c *Curve) Zero(t float64) float64 {
+	if t <= 0 {
+		return FromContinuous(c.rates[0], c.options.Convention)
+	}
+	return FromContinuous(c.logDiscount(t)/t, c.options.Convention)
+}
+
+// Discount is the price of 1 paid in t years
+func (c *Curve) Discount(t float64) float64 {
+	return math.Exp(-c.logDiscount(t))
+}
+
+// Forward is the zero rate from t1 to t2 seen today. when t2 isn't after t1 it's the rate over
+// the day following t1, the short rate at t1
+func (c *Curve) Forward(t1 float64, t2 float64) float64 {
+	if t2 <= t1 {
+		t2 = t1 + 1.0/365
+	}
+	return FromContinuous((c.logDiscount(t2)-c.logDiscount(t1))/(t2-t1), c.options.Convention)
+}
+
+// Points returns the curve at months 0 to months, perYear months a year
+func (c *Curve) Points(months int, perYear int) []Point {
+	if perYear <= 0 {
+		perYear = 12
+	}
+	step := 1 / float64(perYear)
+
+	points := make([]Point, 0, months+1)
+	for month := 0; month <= months; month++ {
+		t := float64(month) * step
+		points = append(points, Point{
+			Month:    month,
+			Years:    t,
+			Zero:     c.Zero(t),
+			Forward:  c.Forward(t, t+step),
+			Discount: c.Discount(t),
+		})
+	}
+	return points
+}
+
+// logDiscount is -ln P(0, t), the continuous zero rate times t
+func (c *Curve) logDiscount(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	n := len(c.tenors)
+	if t <= c.tenors[0] {
+		return c.rates[0] * t
+	}
+	if t <= c.tenors[n-1] {
+		return c.interpolate(t)
+	}
+	return c.extrapolate(t)
+}
+
+func (c *Curve) interpolate(t float64) float64 {
+	i := sort.SearchFloat64s(c.tenors, t)
+	if c.tenors[i] == t {
+		return c.rates[i] * t
+	}
+	t0, t1 := c.tenors[i-1], c.tenors[i]
+	w := (t - t0) / (t1 - t0)
+
+	switch {
+	case c.options.Interpolation == InterpolateFlatForward:
+		return c.rates[i-1]*t0*(1-w) + c.rates[i]*t1*w
+	case c.spline != nil:
+		return splineAt(c.tenors, c.rates, c.spline, i, t) * t
+	}
+	return (c.rates[i-1]*(1-w) + c.rates[i]*w) * t
+}
+
+func (c *Curve) extrapolate(t float64) float64 {
+	n := len(c.tenors)
+	last := c.rates[n-1] * c.tenors[n-1]
+
+	switch c.options.Extrapolation {
+	case ExtrapolateSmithWilson:
+		return c.sw.logDiscount(t)
+	case ExtrapolateFlatForward:
+		forward := c.rates[n-1]
+		if n > 1 {
+			forward = (last - c.rates[n-2]*c.tenors[n-2]) / (c.tenors[n-1] - c.tenors[n-2])
+		}
+		if math.IsNaN(c.ufr) || t <= c.options.UFRStart {
+			return last + forward*(t-c.tenors[n-1])
+		}
+		switchAt := math.Max(c.options.UFRStart, c.tenors[n-1])
+		return last + forward*(switchAt-c.tenors[n-1]) + c.ufr*(t-switchAt)
+	}
+	return c.rates[n-1] * t
+}
+
+// ParseTenor reads tenors such as "1M", "6m", "10Y", "2W" or "30D" in years. plain numbers are years
+func ParseTenor(label string) (float64, error) {
+	s := strings.ToUpper(strings.TrimSpace(label))
+	if s == "" {
+		return 0, fmt.Errorf("empty tenor")
+	}
+
+	unit := 1.0
+	switch s[len(s)-1] {
+	case 'Y':
+		s = s[:len(s)-1]
+	case 'M':
+		s, unit = s[:len(s)-1], 1.0/12
+	case 'W':
+		s, unit = s[:len(s)-1], 7.0/365
+	case 'D':
+		s, unit = s[:len(s)-1], 1.0/365
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid tenor %q", label)
+	}
+	return value * unit, nil
+}
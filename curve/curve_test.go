@@ -0,0 +1,134 @@
+package curve
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	testTenors = []float64{0.5, 1, 2, 3, 5, 7, 10, 20, 30}
+	testRates  = []float64{0.045, 0.044, 0.041, 0.039, 0.038, 0.0385, 0.039, 0.041, 0.04}
+)
+
+func TestParRatesRepriceToPar(t *testing.T) {
+	tests := []struct {
+		name          string
+		frequency     int
+		interpolation Interpolation
+	}{
+		{"annual linear", 1, InterpolateLinear},
+		{"semi-annual linear", 2, InterpolateLinear},
+		{"annual cubic", 1, InterpolateCubic},
+		{"quarterly flat forward", 4, InterpolateFlatForward},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := New(testTenors, testRates, Options{Input: ParRates, Frequency: test.frequency, Interpolation: test.interpolation})
+			if err != nil {
+				t.Fatal(err)
+			}
+			period := 1 / float64(test.frequency)
+			for i, maturity := range testTenors {
+				coupon := testRates[i]
+				// coupons every period back from maturity, the first one a stub
+				var dates []float64
+				for d := maturity; d > 1e-9; d -= period {
+					dates = append([]float64{d}, dates...)
+				}
+				price, previous := 0.0, 0.0
+				for _, d := range dates {
+					price += coupon * (d - previous) * c.Discount(d)
+					previous = d
+				}
+				price += c.Discount(maturity)
+				if math.Abs(price-1) > 1e-9 {
+					t.Errorf("%g year par bond prices at %.12f", maturity, price)
+				}
+			}
+		})
+	}
+}
+
+func TestSmithWilsonConvergesToUFR(t *testing.T) {
+	tests := []struct {
+		name     string
+		ufr      float64
+		ufrStart float64
+	}{
+		{"ufr above the curve", 0.045, 60},
+		{"ufr below the curve", 0.03, 60},
+		{"early convergence", 0.042, 40},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := New(testTenors, testRates, Options{Extrapolation: ExtrapolateSmithWilson, UFR: test.ufr, UFRStart: test.ufrStart})
+			if err != nil {
+				t.Fatal(err)
+			}
+			omega := ToContinuous(test.ufr, Annual)
+			if gap := math.Abs(c.sw.forward(test.ufrStart) - omega); gap > convergenceTolerance+1e-9 {
+				t.Errorf("forward at %g years is %g from the UFR", test.ufrStart, gap)
+			}
+			// the fit still goes through the market rates
+			for i, tenor := range testTenors {
+				if got := FromContinuous(c.sw.logDiscount(tenor)/tenor, Annual); math.Abs(got-testRates[i]) > 1e-9 {
+					t.Errorf("zero rate at %g years is %g, want %g", tenor, got, testRates[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolationPassesThroughNodes(t *testing.T) {
+	for _, interpolation := range []Interpolation{InterpolateLinear, InterpolateCubic, InterpolateFlatForward} {
+		t.Run(string(interpolation), func(t *testing.T) {
+			c, err := New(testTenors, testRates, Options{Interpolation: interpolation})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, tenor := range testTenors {
+				if got := c.Zero(tenor); math.Abs(got-testRates[i]) > 1e-12 {
+					t.Errorf("zero rate at %g years is %g, want %g", tenor, got, testRates[i])
+				}
+			}
+			for _, node := range c.Nodes() {
+				if got := c.Zero(node.Years); math.Abs(got-node.Rate) > 1e-12 {
+					t.Errorf("node %g: zero rate %g, want %g", node.Years, got, node.Rate)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTenor(t *testing.T) {
+	tests := []struct {
+		label string
+		years float64
+		ok    bool
+	}{
+		{"1M", 1.0 / 12, true},
+		{"6m", 0.5, true},
+		{"10Y", 10, true},
+		{" 3y ", 3, true},
+		{"2W", 14.0 / 365, true},
+		{"30D", 30.0 / 365, true},
+		{"0.5", 0.5, true},
+		{"1.5Y", 1.5, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"Y", 0, false},
+		{"-1Y", 0, false},
+		{"1X", 0, false},
+		{"ten years", 0, false},
+	}
+	for _, test := range tests {
+		years, err := ParseTenor(test.label)
+		if (err == nil) != test.ok {
+			t.Errorf("ParseTenor(%q): error %v, want ok %v", test.label, err, test.ok)
+			continue
+		}
+		if test.ok && math.Abs(years-test.years) > 1e-15 {
+			t.Errorf("ParseTenor(%q) = %g, want %g", test.label, years, test.years)
+		}
+	}
+}
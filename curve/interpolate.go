@@ -0,0 +1,38 @@
+package curve
+
+// naturalSpline returns the second derivatives of the natural cubic spline through (x, y)
+func naturalSpline(x []float64, y []float64) []float64 {
+	n := len(x)
+	m := make([]float64, n)
+	if n < 3 {
+		return m
+	}
+
+	// tridiagonal system for the interior second derivatives, solved with the Thomas algorithm
+	c := make([]float64, n)
+	d := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		h0, h1 := x[i]-x[i-1], x[i+1]-x[i]
+		a := h0
+		b := 2 * (h0 + h1)
+		r := 6 * ((y[i+1]-y[i])/h1 - (y[i]-y[i-1])/h0)
+		if i > 1 {
+			b -= a * c[i-1]
+			r -= a * d[i-1]
+		}
+		c[i] = h1 / b
+		d[i] = r / b
+	}
+	for i := n - 2; i >= 1; i-- {
+		m[i] = d[i] - c[i]*m[i+1]
+	}
+	return m
+}
+
+// splineAt evaluates the spline on the interval x[i-1] to x[i]
+func splineAt(x []float64, y []float64, m []float64, i int, t float64) float64 {
+	h := x[i] - x[i-1]
+	a := (x[i] - t) / h
+	b := (t - x[i-1]) / h
+	return a*y[i-1] + b*y[i] + ((a*a*a-a)*m[i-1]+(b*b*b-b)*m[i])*h*h/6
+}
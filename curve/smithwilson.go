@@ -0,0 +1,150 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// convergence speed when there's no convergence point to calibrate to
+	defaultAlpha = 0.1
+	// the calibrated alpha is the smallest in this range bringing the forward within
+	// convergenceTolerance of the UFR at the convergence point
+	minAlpha             = 0.05
+	maxAlpha             = 1.0
+	convergenceTolerance = 0.0001
+)
+
+// smithWilson is the Smith-Wilson curve through zero coupon prices at tenors u, with forwards
+// converging to the continuous UFR omega at speed alpha
+type smithWilson struct {
+	u     []float64
+	zeta  []float64
+	omega float64
+	alpha float64
+}
+
+// fitSmithWilson fits the curve to continuous zero rates at tenors. with alpha 0 it's calibrated
+// to converge by convergence years, or 0.1 when convergence isn't past the last tenor
+func fitSmithWilson(tenors []float64, rates []float64, omega float64, convergence float64, alpha float64) (*smithWilson, error) {
+	if alpha > 0 {
+		return solveSmithWilson(tenors, rates, omega, alpha)
+	}
+	if convergence <= tenors[len(tenors)-1] {
+		return solveSmithWilson(tenors, rates, omega, defaultAlpha)
+	}
+
+	gap := func(alpha float64) (*smithWilson, float64, error) {
+		sw, err := solveSmithWilson(tenors, rates, omega, alpha)
+		if err != nil {
+			return nil, 0, err
+		}
+		return sw, math.Abs(sw.forward(convergence) - omega), nil
+	}
+
+	low, lowGap, err := gap(minAlpha)
+	if err != nil || lowGap <= convergenceTolerance {
+		return low, err
+	}
+	high, highGap, err := gap(maxAlpha)
+	if err != nil || highGap > convergenceTolerance {
+		return high, err
+	}
+
+	lo, hi := minAlpha, maxAlpha
+	for i := 0; i < 50 && hi-lo > 1e-6; i++ {
+		mid := (lo + hi) / 2
+		sw, midGap, err := gap(mid)
+		if err != nil {
+			return nil, err
+		}
+		if midGap <= convergenceTolerance {
+			hi, high = mid, sw
+		} else {
+			lo = mid
+		}
+	}
+	return high, nil
+}
+
+func solveSmithWilson(tenors []float64, rates []float64, omega float64, alpha float64) (*smithWilson, error) {
+	n := len(tenors)
+	sw := &smithWilson{u: tenors, omega: omega, alpha: alpha}
+
+	matrix := make([][]float64, n)
+	target := make([]float64, n)
+	for i := range tenors {
+		matrix[i] = make([]float64, n)
+		for j := range tenors {
+			matrix[i][j] = sw.wilson(tenors[i], tenors[j])
+		}
+		target[i] = math.Exp(-rates[i]*tenors[i]) - math.Exp(-omega*tenors[i])
+	}
+
+	zeta, err := solveLinear(matrix, target)
+	if err != nil {
+		return nil, fmt.Errorf("Smith-Wilson fit: %w", err)
+	}
+	sw.zeta = zeta
+	return sw, nil
+}
+
+// wilson is the Wilson function W(t, u)
+func (sw *smithWilson) wilson(t float64, u float64) float64 {
+	low, high := math.Min(t, u), math.Max(t, u)
+	return math.Exp(-sw.omega*(t+u)) * (sw.alpha*low - 0.5*math.Exp(-sw.alpha*high)*(math.Exp(sw.alpha*low)-math.Exp(-sw.alpha*low)))
+}
+
+func (sw *smithWilson) price(t float64) float64 {
+	p := math.Exp(-sw.omega * t)
+	for j, u := range sw.u {
+		p += sw.zeta[j] * sw.wilson(t, u)
+	}
+	return p
+}
+
+func (sw *smithWilson) logDiscount(t float64) float64 {
+	return -math.Log(sw.price(t))
+}
+
+// forward is the continuous instantaneous forward at t
+func (sw *smithWilson) forward(t float64) float64 {
+	const h = 1e-4
+	return (sw.logDiscount(t+h) - sw.logDiscount(t-h)) / (2 * h)
+}
+
+// solveLinear solves a x = b by Gaussian elimination with partial pivoting
+func solveLinear(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-300 {
+			return nil, fmt.Errorf("singular system")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			f := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+			b[row] -= f * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, nil
+}
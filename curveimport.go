@@ -0,0 +1,545 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"prismic-ui/curve"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// layouts of the curve files ImportSpotRates reads
+const (
+	CurveFileLong      = "long"      // a row per date and tenor: date, tenor, rate (and optionally curve) columns
+	CurveFileWide      = "wide"      // a row per date, a column per tenor
+	CurveFileFRED      = "fred"      // wide with FRED series ids (DGS10, DGS3MO) as columns, "." for missing values
+	CurveFileBloomberg = "bloomberg" // a row per tenor or ticker without a date column, the date in the lines above
+)
+
+var (
+	curveDateColumnNames   = []string{"Date", "observation_date", "AsOf", "As Of", "Curve Date", "Trade Date"}
+	curveTenorColumnNames  = []string{"Tenor", "Maturity", "Term"}
+	curveTickerColumnNames = []string{"Ticker", "Security", "Instrument"}
+	curveRateColumnNames   = []string{"Rate", "Yield", "Mid Yield", "Yield Mid", "Yld Mid", "Mid", "Spot Rate", "Zero Rate", "Market Rate", "PX_LAST", "Last Price", "Value"}
+	curveNameColumnNames   = []string{"Curve", "Series", "Index", "Curve Name"}
+
+	curveDateLayouts = []string{
+		"2006-01-02", "2006/01/02", "01/02/2006", "1/2/2006", "20060102", "02-Jan-2006", "2-Jan-2006",
+		"Jan 2, 2006", "January 2, 2006", "2006-01-02 15:04:05", time.RFC3339,
+	}
+
+	// FRED treasury constant maturity ids, DGS1MO, DGS10
+	fredSeriesPattern = regexp.MustCompile(`^DGS(\d+)(MO)?$`)
+	// Bloomberg generic yields, USGG3M, USGG10YR
+	usggTickerPattern = regexp.MustCompile(`^USGG(\d+)(M|YR)$`)
+	// Bloomberg SOFR swaps, USOSFR10 for 10 years, USOSFRC for 3 months
+	sofrTickerPattern = regexp.MustCompile(`^USOSFR(\d+|[A-K])$`)
+)
+
+// SpotRateImportRequest reads one curve of a scenario config from curve files
+type SpotRateImportRequest struct {
+	Paths     []string // a file, or one FRED series file per tenor
+	Asof      string   // the config's Asof, the latest rate on or before it is taken per tenor
+	Curve     string   // SOFR or TR, picks the rows of files with a curve column
+	Tenors    []string // the config's tenors, every tenor of the files when empty
+	Unit      string   // percent or decimal of the rates returned, the file's unit when empty
+	InputUnit string   // percent or decimal of the files, detected when empty
+}
+
+// SpotRateImport is the curve read, ready for sofrSpotRate or trSpotRate
+type SpotRateImport struct {
+	Paths     []string
+	Format    string
+	Curve     string
+	Asof      string
+	CurveDate string             // latest date of the rates taken
+	Rates     map[string]float64 // keyed by the requested tenor labels
+	Unit      string
+	InputUnit string
+	Missing   []string // requested tenors the files have no rate for
+	Ignored   []string // columns or tenors of the files not imported
+	Warnings  []string
+}
+
+// curveQuote is a rate of the files at one tenor and date
+type curveQuote struct {
+	tenor   string
+	years   float64
+	date    time.Time
+	dated   bool
+	rate    float64
+	percent bool // given with a % sign, already a decimal
+}
+
+// ImportSpotRates reads a curve from local files (CSV with date/tenor/rate columns, FRED
+// series files or a Bloomberg export) for the Generate Inputs spot rates. the rate of each
+// tenor is the latest on or before Asof, converted to the config's unit
+func (a *App) ImportSpotRates(request SpotRateImportRequest) (*SpotRateImport, error) {
+	if len(request.Paths) == 0 {
+		return nil, fmt.Errorf("no curve file given")
+	}
+
+	result := &SpotRateImport{
+		Paths:    request.Paths,
+		Curve:    request.Curve,
+		Asof:     request.Asof,
+		Rates:    make(map[string]float64),
+		Missing:  []string{},
+		Ignored:  []string{},
+		Warnings: []string{},
+	}
+
+	var asof time.Time
+	if request.Asof != "" {
+		var ok bool
+		if asof, ok = parseCurveDate(request.Asof); !ok {
+			return nil, fmt.Errorf("invalid Asof %q", request.Asof)
+		}
+	}
+
+	var quotes []curveQuote
+	for _, path := range request.Paths {
+		format, fileQuotes, ignored, err := readCurveFile(path, request.Curve)
+		if err != nil {
+			runtime.LogError(a.ctx, "Error reading curve file: "+err.Error())
+			return nil, err
+		}
+		if result.Format == "" {
+			result.Format = format
+		} else if result.Format != format {
+			result.Format = "mixed"
+		}
+		quotes = append(quotes, fileQuotes...)
+		result.Ignored = append(result.Ignored, ignored...)
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no rates found in %s", strings.Join(request.Paths, ", "))
+	}
+
+	// latest quote on or before asof per tenor, undated quotes count as of asof
+	latest := make(map[string]curveQuote)
+	var tooLate []string
+	for _, q := range quotes {
+		if q.dated && !asof.IsZero() && q.date.After(asof) {
+			continue
+		}
+		key := tenorKey(q.years)
+		if current, ok := latest[key]; ok {
+			if q.date.Before(current.date) {
+				continue
+			}
+			if q.date.Equal(current.date) && q.rate != current.rate {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s has two rates on the same date, the last one is used", q.tenor))
+			}
+		}
+		latest[key] = q
+	}
+	for _, q := range quotes {
+		if _, ok := latest[tenorKey(q.years)]; !ok && !containsFold(tooLate, q.tenor) {
+			tooLate = append(tooLate, q.tenor)
+		}
+	}
+	if len(latest) == 0 {
+		return nil, fmt.Errorf("no rates on or before %s", request.Asof)
+	}
+	if len(tooLate) > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s only have rates after %s", strings.Join(tooLate, ", "), request.Asof))
+	}
+
+	var curveDate time.Time
+	undated := false
+	for _, q := range latest {
+		if !q.dated {
+			undated = true
+		} else if q.date.After(curveDate) {
+			curveDate = q.date
+		}
+	}
+	if !curveDate.IsZero() {
+		result.CurveDate = curveDate.Format("2006-01-02")
+		var stale []string
+		for _, q := range latest {
+			if q.dated && q.date.Before(curveDate) {
+				stale = append(stale, q.tenor+" ("+q.date.Format("2006-01-02")+")")
+			}
+		}
+		if len(stale) > 0 {
+			sort.Strings(stale)
+			result.Warnings = append(result.Warnings, "older rates used for "+strings.Join(stale, ", "))
+		}
+		if !asof.IsZero() && curveDate.Before(asof) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("no curve on %s, the curve of %s is used", request.Asof, result.CurveDate))
+		}
+	}
+	if undated {
+		result.Warnings = append(result.Warnings, "some rates have no date and are taken as of Asof")
+	}
+
+	// percent when rates have a % sign or any is above 30%, as rateScale decides for configs
+	result.InputUnit = strings.ToLower(request.InputUnit)
+	if result.InputUnit == "" {
+		result.InputUnit = "decimal"
+		for _, q := range latest {
+			if q.percent || math.Abs(q.rate) > 0.3 {
+				result.InputUnit = "percent"
+				break
+			}
+		}
+	}
+	if result.InputUnit != "percent" && result.InputUnit != "decimal" {
+		return nil, fmt.Errorf("unknown input unit %q", request.InputUnit)
+	}
+	result.Unit = strings.ToLower(request.Unit)
+	if result.Unit == "" {
+		result.Unit = result.InputUnit
+	}
+	if result.Unit != "percent" && result.Unit != "decimal" {
+		return nil, fmt.Errorf("unknown unit %q", request.Unit)
+	}
+
+	convert := func(q curveQuote) float64 {
+		decimal := q.rate
+		if !q.percent && result.InputUnit == "percent" {
+			decimal /= 100
+		}
+		// rounded so 4.25 doesn't come back as 4.250000000000001
+		if result.Unit == "percent" {
+			return math.Round(decimal*100*1e10) / 1e10
+		}
+		return math.Round(decimal*1e12) / 1e12
+	}
+
+	if len(request.Tenors) == 0 {
+		for _, q := range latest {
+			result.Rates[q.tenor] = convert(q)
+		}
+		return result, nil
+	}
+
+	requested := make(map[string]bool)
+	for _, label := range request.Tenors {
+		years, err := curve.ParseTenor(label)
+		if err != nil {
+			return nil, err
+		}
+		requested[tenorKey(years)] = true
+		if q, ok := latest[tenorKey(years)]; ok {
+			result.Rates[label] = convert(q)
+		} else {
+			result.Missing = append(result.Missing, label)
+		}
+	}
+	for key, q := range latest {
+		if !requested[key] {
+			result.Ignored = append(result.Ignored, q.tenor)
+		}
+	}
+	sort.Strings(result.Ignored)
+	return result, nil
+}
+
+// readCurveFile reads the quotes of one curve file. lines above the header (Bloomberg
+// exports put the curve name and date there) are searched for the curve date
+func readCurveFile(path string, curveName string) (string, []curveQuote, []string, error) {
+	records, err := readCurveRecords(path)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var preambleDate time.Time
+	for i, header := range records {
+		dateColumn := findHeaderColumn(header, curveDateColumnNames)
+		tenorColumn := findHeaderColumn(header, curveTenorColumnNames)
+		tickerColumn := findHeaderColumn(header, curveTickerColumnNames)
+		rateColumn := findHeaderColumn(header, curveRateColumnNames)
+		nameColumn := findHeaderColumn(header, curveNameColumnNames)
+		rows := records[i+1:]
+
+		keep := func(row []string) bool {
+			return nameColumn < 0 || curveName == "" || matchesCurve(cell(row, nameColumn), curveName)
+		}
+		// without a curve column, Bloomberg tickers tell SOFR swaps from Treasury yields
+		otherCurve := func(names ...string) bool {
+			if nameColumn >= 0 || curveName == "" {
+				return false
+			}
+			for _, name := range names {
+				if family := tickerCurve(name); family != "" {
+					return !matchesCurve(family, curveName)
+				}
+			}
+			return false
+		}
+
+		if rateColumn >= 0 && (tenorColumn >= 0 || tickerColumn >= 0) {
+			format := CurveFileLong
+			if dateColumn < 0 {
+				format = CurveFileBloomberg
+			}
+			var quotes []curveQuote
+			var ignored []string
+			for r, row := range rows {
+				if !keep(row) || otherCurve(cell(row, tickerColumn), cell(row, tenorColumn)) || strings.TrimSpace(cell(row, rateColumn)) == "" {
+					continue
+				}
+				label, years, ok := importTenor(cell(row, tenorColumn))
+				if !ok {
+					label, years, ok = importTenor(cell(row, tickerColumn))
+				}
+				if !ok {
+					ignored = append(ignored, strings.TrimSpace(cell(row, tenorColumn)+" "+cell(row, tickerColumn)))
+					continue
+				}
+				q, err := newCurveQuote(label, years, cell(row, rateColumn))
+				if err != nil {
+					return "", nil, nil, fmt.Errorf("%s: row %d: %w", path, i+r+2, err)
+				}
+				if q == nil {
+					continue
+				}
+				if dateColumn >= 0 {
+					if q.date, q.dated = parseCurveDate(cell(row, dateColumn)); !q.dated {
+						return "", nil, nil, fmt.Errorf("%s: row %d: invalid date %q", path, i+r+2, cell(row, dateColumn))
+					}
+				} else if !preambleDate.IsZero() {
+					q.date, q.dated = preambleDate, true
+				}
+				quotes = append(quotes, *q)
+			}
+			return format, quotes, ignored, nil
+		}
+
+		columns := make(map[int]curveQuote)
+		if dateColumn >= 0 {
+			for c, name := range header {
+				if c == dateColumn || c == nameColumn || otherCurve(name) {
+					continue
+				}
+				if label, years, ok := importTenor(name); ok {
+					columns[c] = curveQuote{tenor: label, years: years}
+				}
+			}
+		}
+		if len(columns) > 0 {
+			format := CurveFileWide
+			var ignored []string
+			for c, name := range header {
+				if c == dateColumn || c == nameColumn || strings.TrimSpace(name) == "" {
+					continue
+				}
+				if _, ok := columns[c]; !ok {
+					ignored = append(ignored, name)
+				} else if fredSeriesPattern.MatchString(strings.ToUpper(strings.TrimSpace(name))) {
+					format = CurveFileFRED
+				}
+			}
+
+			var quotes []curveQuote
+			for r, row := range rows {
+				if !keep(row) || strings.TrimSpace(cell(row, dateColumn)) == "" {
+					continue
+				}
+				date, ok := parseCurveDate(cell(row, dateColumn))
+				if !ok {
+					return "", nil, nil, fmt.Errorf("%s: row %d: invalid date %q", path, i+r+2, cell(row, dateColumn))
+				}
+				for c, column := range columns {
+					q, err := newCurveQuote(column.tenor, column.years, cell(row, c))
+					if err != nil {
+						return "", nil, nil, fmt.Errorf("%s: row %d, column %s: %w", path, i+r+2, header[c], err)
+					}
+					if q != nil {
+						q.date, q.dated = date, true
+						quotes = append(quotes, *q)
+					}
+				}
+			}
+			return format, quotes, ignored, nil
+		}
+
+		// not a header, look for the curve date
+		for _, value := range header {
+			value = strings.TrimSpace(value)
+			if lower := strings.ToLower(value); strings.HasPrefix(lower, "as of") {
+				value = strings.TrimSpace(strings.TrimLeft(value[len("as of"):], " :"))
+			}
+			if date, ok := parseCurveDate(value); ok {
+				preambleDate = date
+				break
+			}
+		}
+	}
+	return "", nil, nil, fmt.Errorf("%s: no date/tenor/rate columns or tenor columns found", path)
+}
+
+// readCurveRecords reads every line of a curve file without fitting rows to the first line,
+// which may be a title
+func readCurveRecords(path string) ([][]string, error) {
+	delimiter, err := detectFileDelimiter(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := openTextFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := newDelimitedReader(file, delimiter)
+	reader.LazyQuotes = true
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvLineError(path, err)
+		}
+		records = append(records, trimTrailingEmpty(record))
+	}
+	return records, nil
+}
+
+// newCurveQuote parses a rate cell, nil for missing values (FRED writes ".")
+func newCurveQuote(label string, years float64, value string) (*curveQuote, error) {
+	value = strings.TrimSpace(value)
+	if value == "." {
+		return nil, nil
+	}
+	rate, blank, ok := parseNumber(value)
+	if blank {
+		return nil, nil
+	}
+	if !ok || math.IsInf(rate, 0) {
+		return nil, fmt.Errorf("invalid rate %q", value)
+	}
+	return &curveQuote{tenor: label, years: years, rate: rate, percent: strings.HasSuffix(value, "%")}, nil
+}
+
+// importTenor reads the tenors of curve files: "1M", "3 Mo", "10 Yr", "2 years", FRED ids
+// (DGS3MO, DGS10) and Bloomberg tickers (USGG10YR Index, USOSFR5 Curncy). the label is the
+// tenor as the configs write it, "3M" or "10Y"
+func importTenor(name string) (string, float64, bool) {
+	s := tickerSymbol(name)
+	if s == "" {
+		return "", 0, false
+	}
+
+	months := -1
+	if m := fredSeriesPattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		months = n * 12
+		if m[2] != "" {
+			months = n
+		}
+	} else if m := usggTickerPattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		months = n * 12
+		if m[2] == "M" {
+			months = n
+		}
+	} else if m := sofrTickerPattern.FindStringSubmatch(s); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			months = n * 12
+		} else {
+			months = int(m[1][0]-'A') + 1
+		}
+	}
+	if months > 0 {
+		years := float64(months) / 12
+		return tenorLabel(years), years, true
+	}
+
+	s = strings.NewReplacer(" ", "", "YEARS", "Y", "YEAR", "Y", "YRS", "Y", "YR", "Y", "MONTHS", "M", "MONTH", "M", "MTHS", "M", "MTH", "M", "MO", "M",
+		"WEEKS", "W", "WEEK", "W", "WK", "W", "DAYS", "D", "DAY", "D").Replace(s)
+	if s == "" || !strings.ContainsAny(s[len(s)-1:], "YMWD") {
+		// plain numbers are years in ParseTenor, too loose for column names
+		return "", 0, false
+	}
+	years, err := curve.ParseTenor(s)
+	if err != nil || years <= 0 {
+		return "", 0, false
+	}
+	return tenorLabel(years), years, true
+}
+
+// tickerSymbol is a name in upper case without the Bloomberg yellow key, USGG10YR for
+// "USGG10YR Index"
+func tickerSymbol(name string) string {
+	s := strings.ToUpper(strings.TrimSpace(name))
+	if i := strings.IndexAny(s, " "); i > 0 && (strings.HasSuffix(s, " INDEX") || strings.HasSuffix(s, " CURNCY") || strings.HasSuffix(s, " GOVT")) {
+		s = s[:i]
+	}
+	return s
+}
+
+// tickerCurve is the curve of a ticker or series id, SOFR for USOSFR swaps and TR for USGG
+// yields and FRED treasury series, empty for other names
+func tickerCurve(name string) string {
+	s := tickerSymbol(name)
+	switch {
+	case sofrTickerPattern.MatchString(s):
+		return "SOFR"
+	case usggTickerPattern.MatchString(s), fredSeriesPattern.MatchString(s):
+		return "TR"
+	}
+	return ""
+}
+
+// tenorLabel writes a tenor in years as "3M", "1Y" or "10Y", days and weeks as "1D" or "2W"
+func tenorLabel(years float64) string {
+	if months := years * 12; math.Abs(months-math.Round(months)) < 1e-9 {
+		if m := int(math.Round(months)); m%12 == 0 {
+			return strconv.Itoa(m/12) + "Y"
+		}
+		return strconv.Itoa(int(math.Round(months))) + "M"
+	}
+	if days := years * 365; math.Abs(days-math.Round(days)) < 1e-6 {
+		if d := int(math.Round(days)); d%7 == 0 {
+			return strconv.Itoa(d/7) + "W"
+		}
+		return strconv.Itoa(int(math.Round(days))) + "D"
+	}
+	return strconv.FormatFloat(years, 'f', -1, 64) + "Y"
+}
+
+// tenorKey compares tenors by their length, so "12M" matches "1Y"
+func tenorKey(years float64) string {
+	return fmt.Sprintf("%.6f", years)
+}
+
+func parseCurveDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range curveDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// matchesCurve tells whether a curve column value is the SOFR or Treasury curve
+func matchesCurve(value string, curveName string) bool {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	switch strings.ToUpper(strings.TrimSpace(curveName)) {
+	case "TR", "TREASURY", "UST":
+		return value == "TR" || strings.Contains(value, "TREASURY") || strings.Contains(value, "UST") || strings.Contains(value, "GOVT")
+	default:
+		return strings.Contains(value, strings.ToUpper(strings.TrimSpace(curveName)))
+	}
+}
+
+func cell(row []string, column int) string {
+	if column < 0 || column >= len(row) {
+		return ""
+	}
+	return row[column]
+}
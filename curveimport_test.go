@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestReadCurveFileFiltersTickerFamily(t *testing.T) {
+	files := map[string]string{
+		"bloomberg": "US curves\nAs of 03/29/2024\nTicker,PX_LAST\nUSGG3M Index,5.36\nUSGG10YR Index,4.20\nUSOSFRC Curncy,5.30\nUSOSFR10 Curncy,3.85\n",
+		"wide":      "Date,USGG10YR Index,USOSFR10 Curncy,USOSFR5 Curncy\n2024-03-29,4.20,3.85,3.90\n",
+	}
+	tests := []struct {
+		file   string
+		curve  string
+		tenors []string
+	}{
+		{"bloomberg", "SOFR", []string{"10Y", "3M"}},
+		{"bloomberg", "TR", []string{"10Y", "3M"}},
+		{"bloomberg", "", []string{"10Y", "10Y", "3M", "3M"}},
+		{"wide", "SOFR", []string{"10Y", "5Y"}},
+		{"wide", "Treasury", []string{"10Y"}},
+	}
+	folder := t.TempDir()
+	for _, test := range tests {
+		path := filepath.Join(folder, test.file+".csv")
+		if err := os.WriteFile(path, []byte(files[test.file]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, quotes, _, err := readCurveFile(path, test.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tenors []string
+		for _, q := range quotes {
+			tenors = append(tenors, q.tenor)
+		}
+		sort.Strings(tenors)
+		if strings.Join(tenors, ",") != strings.Join(test.tenors, ",") {
+			t.Errorf("%s, curve %q: got tenors %v, want %v", test.file, test.curve, tenors, test.tenors)
+		}
+	}
+
+	// the rates kept are the curve's own
+	path := filepath.Join(folder, "bloomberg.csv")
+	_, quotes, _, err := readCurveFile(path, "SOFR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range quotes {
+		if q.tenor == "10Y" && q.rate != 3.85 {
+			t.Errorf("SOFR 10Y rate %g, want 3.85", q.rate)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"prismic-ui/curve"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ScenarioCurves are the base curves of a scenario config, as the generator builds them
+type ScenarioCurves struct {
+	Asof          string
+	Convention    string // annual or continuous
+	Interpolation string
+	Extrapolation string
+	Unit          string // percent or decimal, as the config's spot rates
+	MonthPerYear  int
+	Months        int
+	Curves        []ScenarioCurve
+}
+
+// ScenarioCurve is one curve with its input tenors and monthly points
+type ScenarioCurve struct {
+	Name   string // SOFR or TR
+	Loop   string // outer or inner, which differ in their UFR
+	UFR    float64
+	Alpha  float64 // Smith-Wilson convergence speed, 0 for other extrapolations
+	Nodes  []CurveNode
+	Points []CurvePoint
+}
+
+type CurveNode struct {
+	Tenor string
+	Years float64
+	Rate  float64 // spot rate given in the config
+	Zero  float64 // zero rate of the curve at the tenor
+}
+
+// CurvePoint is the curve at one month, rates in the config's convention and unit
+type CurvePoint struct {
+	Month    int
+	Years    float64
+	Zero     float64
+	Forward  float64 // forward rate over the following month
+	Discount float64
+}
+
+// BuildCurve builds the SOFR and Treasury zero and forward curves of a scenario config and
+// returns them month by month, for plotting and validating the spot rates and UFR settings
+func (a *App) BuildCurve(config ScenarioConfig) (*ScenarioCurves, error) {
+	if len(config.SofrSpotRate) == 0 && len(config.TrSpotRate) == 0 {
+		return nil, fmt.Errorf("no spot rates")
+	}
+	monthPerYear := config.MonthPerYear
+	if monthPerYear <= 0 {
+		monthPerYear = 12
+	}
+
+	scale := rateScale(config.SofrSpotRate, config.TrSpotRate)
+	result := &ScenarioCurves{
+		Asof:          config.Asof,
+		Convention:    string(curve.ParseConvention(config.RateConvention)),
+		Interpolation: config.Interpolation,
+		Extrapolation: config.Extrapolation,
+		Unit:          "decimal",
+		MonthPerYear:  monthPerYear,
+		Months:        curveHorizon(&config) * monthPerYear,
+	}
+	if scale != 1 {
+		result.Unit = "percent"
+	}
+
+	loops := []struct {
+		name     string
+		ufr      float64
+		ufrStart int
+	}{
+		{"outer", config.UFROuter, config.UFROuterStartMonth},
+		{"inner", config.UFRInner, config.UFRInnerStartMonth},
+	}
+	for _, loop := range loops {
+		for _, c := range []struct {
+			name  string
+			rates map[string]float64
+		}{
+			{"SOFR", config.SofrSpotRate},
+			{"TR", config.TrSpotRate},
+		} {
+			if len(c.rates) == 0 {
+				continue
+			}
+			built, err := scenarioCurve(&config, c.rates, scale, loop.ufr, loop.ufrStart, monthPerYear)
+			if err != nil {
+				runtime.LogError(a.ctx, "Error building curve: "+err.Error())
+				return nil, fmt.Errorf("%s spot rates: %w", c.name, err)
+			}
+			result.Curves = append(result.Curves, describeCurve(c.name, loop.name, loop.ufr, built, c.rates, scale, result.Months, monthPerYear))
+		}
+	}
+	return result, nil
+}
+
+// scenarioCurve builds the curve of one spot rate map with the config's convention,
+// interpolation and extrapolation. rates and UFR are in the config's unit, scale being 100
+// for percent
+func scenarioCurve(config *ScenarioConfig, rates map[string]float64, scale float64, ufr float64, ufrStartMonth int, monthPerYear int) (*curve.Curve, error) {
+	interpolation, err := curve.ParseInterpolation(config.Interpolation)
+	if err != nil {
+		return nil, err
+	}
+	extrapolation, err := curve.ParseExtrapolation(config.Extrapolation)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := make(map[string]float64, len(rates))
+	for tenor, rate := range rates {
+		decimals[tenor] = rate / scale
+	}
+
+	options := curve.Options{
+		Convention:    curve.ParseConvention(config.RateConvention),
+		Interpolation: interpolation,
+		Extrapolation: extrapolation,
+	}
+	// a UFR only applies to the extrapolations converging to it
+	if extrapolation != curve.ExtrapolateFlat && ufr != 0 {
+		options.UFR = ufr / scale
+		options.UFRStart = float64(ufrStartMonth) / float64(monthPerYear)
+	}
+	return curve.FromTenors(decimals, options)
+}
+
+func describeCurve(name string, loop string, ufr float64, built *curve.Curve, rates map[string]float64, scale float64, months int, monthPerYear int) ScenarioCurve {
+	result := ScenarioCurve{Name: name, Loop: loop, UFR: ufr, Alpha: built.Alpha()}
+
+	for tenor, rate := range rates {
+		years, err := curve.ParseTenor(tenor)
+		if err != nil {
+			continue
+		}
+		result.Nodes = append(result.Nodes, CurveNode{Tenor: tenor, Years: years, Rate: rate, Zero: built.Zero(years) * scale})
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool { return result.Nodes[i].Years < result.Nodes[j].Years })
+
+	for _, point := range built.Points(months, monthPerYear) {
+		result.Points = append(result.Points, CurvePoint{
+			Month:    point.Month,
+			Years:    point.Years,
+			Zero:     point.Zero * scale,
+			Forward:  point.Forward * scale,
+			Discount: point.Discount,
+		})
+	}
+	return result
+}
+
+// curveHorizon is the number of years plotted: the projection, the longest tenor and the
+// UFR convergence, whichever is furthest
+func curveHorizon(config *ScenarioConfig) int {
+	years := config.NumberOfYears
+	for _, n := range []int{config.NumberOfYearsInner, config.NumberOfYearSuperSet} {
+		if n > years {
+			years = n
+		}
+	}
+
+	monthPerYear := config.MonthPerYear
+	if monthPerYear <= 0 {
+		monthPerYear = 12
+	}
+	for _, month := range []int{config.UFROuterStartMonth, config.UFRInnerStartMonth} {
+		if y := int(math.Ceil(float64(month) / float64(monthPerYear))); y > years {
+			years = y
+		}
+	}
+	for _, rates := range []map[string]float64{config.SofrSpotRate, config.TrSpotRate} {
+		for tenor := range rates {
+			if t, err := curve.ParseTenor(tenor); err == nil && int(math.Ceil(t)) > years {
+				years = int(math.Ceil(t))
+			}
+		}
+	}
+	return years
+}
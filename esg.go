@@ -0,0 +1,641 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"prismic-ui/curve"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// scenario generation engines, as for post processing
+const (
+	ScenarioEngineNative = PostProcessNative
+	ScenarioEnginePython = PostProcessPython
+)
+
+// files written by GenerateScenarios to <output_path>/<run_id>, named as ESG_deterministic.py
+// names them (see esgScenarioFileName) and laid out as esgScenarioFileFormat:
+//
+//	<run_id>_sofr_outer.csv, <run_id>_tr_outer.csv   a scenario per BaseShockScenarios entry
+//	<run_id>_sofr_inner_<shock>.csv, <run_id>_tr_...   each InnerShockScenarios entry
+//	scenario_generation.json                           ScenarioGenerationResult, not part of the hash
+const scenarioGenerationFile = "scenario_generation.json"
+
+// ScenarioGenerationResult describes the scenario files written for a scenario config
+type ScenarioGenerationResult struct {
+	RunID        string
+	ConfigPath   string
+	ConfigSHA256 string
+	OutputFolder string
+	Engine       string // native or python
+	CreatedAt    string
+	DurationMs   int64
+	Files        []GeneratedScenarioFile
+	Hash         string                     // SHA-256 over the names and contents of Files, equal configs give equal hashes
+	Output       string                     // script output (python only)
+	Stochastic   *StochasticScenarioRequest // model parameters of stochastic sets, nil for deterministic ones
+}
+
+type GeneratedScenarioFile struct {
+	Path      string
+	Curve     string // SOFR or TR
+	Loop      string // outer or inner
+	Scenarios int
+	Rows      int
+	SHA256    string
+}
+
+// ScenarioProgress is emitted as "scenarioProgress" while scenarios are generated
+type ScenarioProgress struct {
+	RunID   string
+	Stage   string // curves, outer, inner, hashing or done
+	Done    int
+	Total   int
+	Message string
+}
+
+// GenerateScenarios writes the deterministic ESG scenarios of a scenario config.
+// ESG_deterministic.py runs instead when the ui config sets usePythonGenerator, its files
+// being hashed afterwards as the built in generator's are
+func (a *App) GenerateScenarios(configPath string) (*ScenarioGenerationResult, error) {
+	started := time.Now()
+
+	uiConfig, err := a.ReadUIConfig()
+	if err == nil && uiConfig.UsePythonGenerator && uiConfig.PythonGenerateScenarioScript != "" {
+		return a.generateScenariosWithPython(uiConfig.PythonGenerateScenarioScript, configPath, started)
+	}
+
+	config, err := a.ReadScenarioConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScenarioGenerationResult{
+		RunID:        config.RunID,
+		ConfigPath:   configPath,
+		OutputFolder: scenarioOutputFolder(configPath, config),
+		Engine:       ScenarioEngineNative,
+		CreatedAt:    started.Format(time.RFC3339),
+	}
+	if result.ConfigSHA256, err = fileSHA256(configPath); err != nil {
+		return nil, err
+	}
+
+	progress := func(stage string, done int, total int, message string) {
+		runtime.EventsEmit(a.ctx, "scenarioProgress", ScenarioProgress{RunID: config.RunID, Stage: stage, Done: done, Total: total, Message: message})
+	}
+
+	if err := generateScenarioFiles(configPath, config, result, progress); err != nil {
+		runtime.LogError(a.ctx, "Error generating scenarios: "+err.Error())
+		progress("failed", 0, 0, err.Error())
+		return nil, err
+	}
+
+	if err := a.finishScenarioGeneration(result, started, progress); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (a *App) generateScenariosWithPython(script string, configPath string, started time.Time) (*ScenarioGenerationResult, error) {
+	// the script takes the config's file name and looks it up in its configs folder
+	output, err := a.ExecutePythonScript(script, []string{filepath.Base(configPath)})
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := a.ReadScenarioConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	result := &ScenarioGenerationResult{
+		RunID:        config.RunID,
+		ConfigPath:   configPath,
+		OutputFolder: scenarioOutputFolder(configPath, config),
+		Engine:       ScenarioEnginePython,
+		CreatedAt:    started.Format(time.RFC3339),
+		Output:       output,
+	}
+	if result.ConfigSHA256, err = fileSHA256(configPath); err != nil {
+		return nil, err
+	}
+
+	names, err := esgScenarioFileNames(config)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, name := range names {
+		path := filepath.Join(result.OutputFolder, name)
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, name)
+			continue
+		}
+		result.Files = append(result.Files, GeneratedScenarioFile{Path: path})
+	}
+	if len(missing) > 0 {
+		// hashed as far as written, the describe view reports the rest as missing
+		runtime.LogWarningf(a.ctx, "%s didn't write %s", filepath.Base(script), strings.Join(missing, ", "))
+	}
+
+	progress := func(stage string, done int, total int, message string) {
+		runtime.EventsEmit(a.ctx, "scenarioProgress", ScenarioProgress{RunID: config.RunID, Stage: stage, Done: done, Total: total, Message: message})
+	}
+	if err := a.finishScenarioGeneration(result, started, progress); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// finishScenarioGeneration hashes the files written and saves the result next to them
+func (a *App) finishScenarioGeneration(result *ScenarioGenerationResult, started time.Time, progress func(stage string, done int, total int, message string)) error {
+	progress("hashing", 0, len(result.Files), "")
+	if err := hashScenarioFiles(result); err != nil {
+		runtime.LogError(a.ctx, "Error hashing scenario files: "+err.Error())
+		return err
+	}
+	result.DurationMs = time.Since(started).Milliseconds()
+
+	if err := writeJSONFile(filepath.Join(result.OutputFolder, scenarioGenerationFile), result); err != nil {
+		runtime.LogError(a.ctx, "Error writing scenario generation summary: "+err.Error())
+		return err
+	}
+	a.fileCache.invalidate(result.OutputFolder)
+
+	progress("done", len(result.Files), len(result.Files), result.Hash)
+	runtime.LogInfof(a.ctx, "Generated %d scenario files for %s in %dms, hash %s", len(result.Files), result.RunID, result.DurationMs, result.Hash)
+	return nil
+}
+
+// scenarioOutputFolder is <output_path>/<run_id>, output_path being relative to the config's folder
+func scenarioOutputFolder(configPath string, config *ScenarioConfig) string {
+	output := config.OutputPath
+	if output == "" {
+		output = "."
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(filepath.Dir(configPath), output)
+	}
+	return filepath.Join(output, config.RunID)
+}
+
+// resolveConfigPath resolves a file named in a scenario config against the config's folder
+func resolveConfigPath(configPath string, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(configPath), path)
+}
+
+func generateScenarioFiles(configPath string, config *ScenarioConfig, result *ScenarioGenerationResult, progress func(stage string, done int, total int, message string)) error {
+	if strings.TrimSpace(config.RunID) == "" {
+		return fmt.Errorf("run_id is empty")
+	}
+	if config.NumberOfYears <= 0 {
+		return fmt.Errorf("NumberOfYears must be positive, got %d", config.NumberOfYears)
+	}
+	if len(config.SofrSpotRate) == 0 || len(config.TrSpotRate) == 0 {
+		return fmt.Errorf("sofrSpotRate and trSpotRate need at least one tenor")
+	}
+	monthPerYear := config.MonthPerYear
+	if monthPerYear <= 0 {
+		monthPerYear = 12
+	}
+
+	progress("curves", 0, 0, "")
+	tenors, err := scenarioTenors(config)
+	if err != nil {
+		return err
+	}
+	shocks, err := readShockFile(resolveConfigPath(configPath, config.ShockInputFile))
+	if err != nil {
+		return err
+	}
+
+	// spot rates and UFRs are in the config's unit (percent or decimal), shocks in basis points
+	scale := rateScale(config.SofrSpotRate, config.TrSpotRate)
+	curves := []struct {
+		name  string
+		rates map[string]float64
+	}{
+		{"SOFR", config.SofrSpotRate},
+		{"TR", config.TrSpotRate},
+	}
+
+	if err := os.MkdirAll(result.OutputFolder, 0755); err != nil {
+		return err
+	}
+
+	loops, err := esgScenarioLoops(config, monthPerYear)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, loop := range loops {
+		total += len(curves) * len(loop.shocks) * len(loop.projection)
+	}
+	done := 0
+
+	for _, loop := range loops {
+		format := esgScenarioFileFormat(loop.inner, tenors, scale)
+
+		for _, c := range curves {
+			base, err := scenarioCurve(config, c.rates, scale, loop.ufr, loop.ufrStart, monthPerYear)
+			if err != nil {
+				return fmt.Errorf("%s spot rates: %w", c.name, err)
+			}
+
+			// the outer set is one file with a scenario per shock, each inner shock a file of its own
+			sets := [][]string{loop.shocks}
+			if loop.inner {
+				sets = nil
+				for _, shockName := range loop.shocks {
+					sets = append(sets, []string{shockName})
+				}
+			}
+
+			for _, set := range sets {
+				shock := ""
+				if loop.inner {
+					shock = set[0]
+				}
+				writer, err := newScenarioFileWriter(filepath.Join(result.OutputFolder, esgScenarioFileName(config.RunID, c.name, loop.inner, shock)), format)
+				if err != nil {
+					return err
+				}
+
+				for i, shockName := range set {
+					points, err := shocks.lookup(shockName)
+					if err != nil {
+						writer.file.Close()
+						return err
+					}
+					for _, start := range loop.projection {
+						if err := writeScenarioPath(writer, config, base, loop, i, shockName, points, start, monthPerYear); err != nil {
+							writer.file.Close()
+							return err
+						}
+						done++
+						progress(strings.ToLower(loop.name), done, total, c.name+" "+shockName)
+					}
+				}
+
+				if err := writer.close(); err != nil {
+					return err
+				}
+				result.Files = append(result.Files, GeneratedScenarioFile{
+					Path:      writer.path,
+					Curve:     c.name,
+					Loop:      strings.ToLower(loop.name),
+					Scenarios: len(set),
+					Rows:      writer.rows,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// esgScenarioLoops are the outer loop over BaseShockScenarios and, when the config has inner
+// shocks and projection months, the inner loop over InnerShockScenarios
+func esgScenarioLoops(config *ScenarioConfig, monthPerYear int) ([]scenarioLoop, error) {
+	outer := scenarioLoop{
+		name:         "Outer",
+		shocks:       config.BaseShockScenarios,
+		months:       config.NumberOfYears * monthPerYear,
+		ufr:          config.UFROuter,
+		ufrStart:     config.UFROuterStartMonth,
+		projection:   []int{0},
+		shockedMonth: config.DictShockedMonth,
+	}
+	if len(outer.shocks) == 0 {
+		outer.shocks = []string{"Base"}
+	}
+	loops := []scenarioLoop{outer}
+
+	innerMonths, err := projectionMonths(config.ListOfInnerProjectionMonth)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.InnerShockScenarios) > 0 && len(innerMonths) > 0 {
+		years := config.NumberOfYearsInner
+		if years <= 0 {
+			years = config.NumberOfYears
+		}
+		loops = append(loops, scenarioLoop{
+			name:         "Inner",
+			shocks:       config.InnerShockScenarios,
+			months:       years * monthPerYear,
+			ufr:          config.UFRInner,
+			ufrStart:     config.UFRInnerStartMonth,
+			projection:   innerMonths,
+			shockedMonth: config.DictShockedMonth,
+			inner:        true,
+		})
+	}
+	return loops, nil
+}
+
+// esgScenarioFileName names a file as ESG_deterministic.py does: <run_id>_<sofr|tr>_outer.csv,
+// and <run_id>_<sofr|tr>_inner_<shock>.csv for the inner shocks, the base shock being
+// <run_id>_<sofr|tr>_inner.csv
+func esgScenarioFileName(runID string, curveName string, inner bool, shock string) string {
+	name := runID + "_" + strings.ToLower(curveName)
+	switch {
+	case !inner:
+		name += "_outer"
+	case isBaseShock(shock):
+		name += "_inner"
+	default:
+		name += "_inner_" + strings.ToLower(strings.TrimSpace(shock))
+	}
+	return name + ".csv"
+}
+
+// esgScenarioFileNames lists the files a scenario config's deterministic set is made of
+func esgScenarioFileNames(config *ScenarioConfig) ([]string, error) {
+	loops, err := esgScenarioLoops(config, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, loop := range loops {
+		for _, curveName := range []string{"SOFR", "TR"} {
+			if !loop.inner {
+				names = append(names, esgScenarioFileName(config.RunID, curveName, false, ""))
+				continue
+			}
+			for _, shock := range loop.shocks {
+				names = append(names, esgScenarioFileName(config.RunID, curveName, true, shock))
+			}
+		}
+	}
+	return names, nil
+}
+
+// esgScenarioFileFormat is the layout of the deterministic files: Scenario, ProjectionMonth
+// (inner files only), Month and one column per tenor, scenarios counted from 1 and months from 0
+// in the config's rate unit
+func esgScenarioFileFormat(inner bool, tenors []scenarioTenor, scale float64) *scenarioFileFormat {
+	header := []string{"Scenario"}
+	if inner {
+		header = append(header, "ProjectionMonth")
+	}
+	header = append(header, "Month")
+	for _, tenor := range tenors {
+		header = append(header, tenor.label)
+	}
+	layout, _ := newScenarioLayout("", header)
+	return &scenarioFileFormat{layout: layout, tenors: tenors, comma: ',', scenarioBase: 1, scale: scale}
+}
+
+// scenarioLoop is the outer set, or the inner set branching off at each projection month
+type scenarioLoop struct {
+	name         string
+	shocks       []string
+	months       int // months projected from each start
+	ufr          float64
+	ufrStart     int   // maturity in months from which forwards are the UFR
+	projection   []int // start months, 0 for the outer loop
+	shockedMonth map[string]int
+	inner        bool
+}
+
+// writeScenarioPath projects one shock from a start month: at each month the curve is the base
+// curve rolled forward (kept as is when ifFlat is set) plus the shock, graded in linearly over
+// DictShockedMonth months and dropped from ResetMonth when ifReset is set
+func writeScenarioPath(writer *scenarioFileWriter, config *ScenarioConfig, base *curve.Curve, loop scenarioLoop, scenario int, shockName string, shock []shockPoint, start int, monthPerYear int) error {
+	rampMonths := loop.shockedMonth[shockName]
+	rates := make([]float64, len(writer.format.tenors))
+
+	for month := 0; month <= loop.months; month++ {
+		t := float64(start+month) / float64(monthPerYear)
+		if config.IfFlat == 1 {
+			t = 0
+		}
+
+		weight := 1.0
+		if rampMonths > 0 && month < rampMonths {
+			weight = float64(month) / float64(rampMonths)
+		}
+		if config.IfReset == 1 && start+month >= config.ResetMonth {
+			weight = 0
+		}
+
+		for k, tenor := range writer.format.tenors {
+			rates[k] = base.Forward(t, t+tenor.years) + weight*interpolateShock(shock, tenor.years)/10000
+		}
+		if err := writer.write(scenario, start, month, rates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rates are written with a fixed number of decimals so the files hash the same on every machine
+func formatRate(rate float64) string {
+	s := strconv.FormatFloat(rate, 'f', 10, 64)
+	if s == "-0.0000000000" {
+		s = "0.0000000000"
+	}
+	return s
+}
+
+// hashScenarioFiles sets the SHA-256 of every file and the run's hash over the file names
+// and hashes, in name order
+func hashScenarioFiles(result *ScenarioGenerationResult) error {
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].Path < result.Files[j].Path })
+
+	hash := sha256.New()
+	for i := range result.Files {
+		sum, err := fileSHA256(result.Files[i].Path)
+		if err != nil {
+			return err
+		}
+		result.Files[i].SHA256 = sum
+		fmt.Fprintf(hash, "%s  %s\n", sum, filepath.Base(result.Files[i].Path))
+	}
+	result.Hash = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+type scenarioTenor struct {
+	label string
+	years float64
+}
+
+// scenarioTenors returns TenorsOfInterests, or the spot rate tenors when it's empty
+func scenarioTenors(config *ScenarioConfig) ([]scenarioTenor, error) {
+	labels := config.TenorsOfInterests
+	if len(labels) == 0 {
+		for label := range config.SofrSpotRate {
+			labels = append(labels, label)
+		}
+	}
+
+	tenors := make([]scenarioTenor, 0, len(labels))
+	for _, label := range labels {
+		years, err := curve.ParseTenor(label)
+		if err != nil {
+			return nil, err
+		}
+		tenors = append(tenors, scenarioTenor{label: label, years: years})
+	}
+	if len(config.TenorsOfInterests) == 0 {
+		sort.SliceStable(tenors, func(i, j int) bool { return tenors[i].years < tenors[j].years })
+	}
+	return tenors, nil
+}
+
+// rateScale is 100 when the spot rates are in percent and 1 when they are decimals
+func rateScale(curves ...map[string]float64) float64 {
+	for _, rates := range curves {
+		for _, rate := range rates {
+			if math.Abs(rate) > 0.3 {
+				return 100
+			}
+		}
+	}
+	return 1
+}
+
+// projectionMonths reads listOfInnerProjectionMonth, a list of numbers or a comma separated string
+func projectionMonths(value interface{}) ([]int, error) {
+	var items []interface{}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		items = v
+	case string:
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				items = append(items, part)
+			}
+		}
+	default:
+		items = []interface{}{v}
+	}
+
+	months := make([]int, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case float64:
+			months = append(months, int(v))
+		case string:
+			month, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("invalid inner projection month %q", v)
+			}
+			months = append(months, month)
+		default:
+			return nil, fmt.Errorf("invalid inner projection month %v", v)
+		}
+	}
+	return months, nil
+}
+
+// shockPoint is a shock in basis points at one tenor
+type shockPoint struct {
+	years float64
+	bps   float64
+}
+
+// shockTable holds the shocks of a ShockInputFile: a shock name column followed by one
+// column per tenor, in basis points
+type shockTable map[string][]shockPoint
+
+// readShockFile reads a ShockInputFile. no file means every shock must be the unshocked base
+func readShockFile(path string) (shockTable, error) {
+	shocks := make(shockTable)
+	if path == "" {
+		return shocks, nil
+	}
+
+	data, err := parseCSVFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading shock file: %w", err)
+	}
+	if len(data) == 0 {
+		return shocks, nil
+	}
+
+	years := make([]float64, len(data[0]))
+	for col := 1; col < len(data[0]); col++ {
+		if years[col], err = curve.ParseTenor(data[0][col]); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	for i, record := range data[1:] {
+		name := strings.TrimSpace(record[0])
+		if name == "" {
+			continue
+		}
+		var points []shockPoint
+		for col := 1; col < len(data[0]) && col < len(record); col++ {
+			cell := strings.TrimSpace(record[col])
+			if cell == "" {
+				continue
+			}
+			bps, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: row %d, column %s: invalid shock %q", path, i+2, data[0][col], cell)
+			}
+			points = append(points, shockPoint{years: years[col], bps: bps})
+		}
+		sort.Slice(points, func(a, b int) bool { return points[a].years < points[b].years })
+		shocks[name] = points
+	}
+	return shocks, nil
+}
+
+// lookup finds a shock by name, ignoring case. base shocks need not be in the file
+func (s shockTable) lookup(name string) ([]shockPoint, error) {
+	if points, ok := s[name]; ok {
+		return points, nil
+	}
+	for key, points := range s {
+		if strings.EqualFold(key, name) {
+			return points, nil
+		}
+	}
+	if isBaseShock(name) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("shock %q is not in the shock input file", name)
+}
+
+// isBaseShock reports whether a shock name is the unshocked base curve
+func isBaseShock(name string) bool {
+	name = strings.TrimSpace(name)
+	return strings.EqualFold(name, "base") || strings.EqualFold(name, "baseline")
+}
+
+// interpolateShock interpolates a shock linearly between tenors, flat beyond the first and last
+func interpolateShock(points []shockPoint, years float64) float64 {
+	n := len(points)
+	switch {
+	case n == 0:
+		return 0
+	case years <= points[0].years:
+		return points[0].bps
+	case years >= points[n-1].years:
+		return points[n-1].bps
+	}
+	i := sort.Search(n, func(i int) bool { return points[i].years >= years })
+	w := (years - points[i-1].years) / (points[i].years - points[i-1].years)
+	return points[i-1].bps*(1-w) + points[i].bps*w
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testdata/esg/expected pins the file names and columns of the deterministic set, with values
+// that can be checked by hand: flat 3% SOFR and 4% Treasury curves, so every forward is the spot
+// rate, Up100 graded in over two months and U25 applied at once
+func TestGenerateScenarioFilesFixture(t *testing.T) {
+	folder := t.TempDir()
+	for _, name := range []string{"config_ESG_OTF_1.json", "shocks.csv"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "esg", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(folder, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configPath := filepath.Join(folder, "config_ESG_OTF_1.json")
+	config, err := NewApp().ReadScenarioConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generate := func() *ScenarioGenerationResult {
+		result := &ScenarioGenerationResult{OutputFolder: scenarioOutputFolder(configPath, config)}
+		if err := generateScenarioFiles(configPath, config, result, func(string, int, int, string) {}); err != nil {
+			t.Fatal(err)
+		}
+		if err := hashScenarioFiles(result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	result := generate()
+
+	expected, err := filepath.Glob(filepath.Join("testdata", "esg", "expected", "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got []string
+	for _, path := range expected {
+		want = append(want, filepath.Base(path))
+	}
+	for _, file := range result.Files {
+		got = append(got, filepath.Base(file.Path))
+	}
+	sort.Strings(want)
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrote %v, want %v", got, want)
+	}
+
+	names, err := esgScenarioFileNames(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("esgScenarioFileNames %v, want %v", names, want)
+	}
+
+	checkExpectedScenarioFiles(t, result.OutputFolder)
+
+	if again := generate(); again.Hash != result.Hash || result.Hash == "" {
+		t.Errorf("hash %q then %q", result.Hash, again.Hash)
+	}
+}
+
+// checkExpectedScenarioFiles compares the files of testdata/esg/expected with those in folder
+func checkExpectedScenarioFiles(t *testing.T, folder string) {
+	t.Helper()
+	expected, err := filepath.Glob(filepath.Join("testdata", "esg", "expected", "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range expected {
+		wantData, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotData, err := os.ReadFile(filepath.Join(folder, filepath.Base(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(gotData) != string(wantData) {
+			t.Errorf("%s:\n%s\nwant\n%s", filepath.Base(path), gotData, wantData)
+		}
+	}
+}
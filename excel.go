@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	excelMaxRows      = 1048576 // rows per worksheet, header included
+	excelMaxSheetName = 31
+)
+
+// ExcelSheet is an output file to add to the workbook as its own sheet
+type ExcelSheet struct {
+	Path string
+	Name string // sheet name, derived from the file when empty
+}
+
+// ExcelExportRequest describes the workbook written by ExportRunToExcel
+type ExcelExportRequest struct {
+	RunName             string
+	Sheets              []ExcelSheet // selected outputs, e.g. BEL summary, liability output, SAA parsed results
+	LiabilityConfigPath string       // liability_config.json used by the run, written to the "Config" sheet
+	DestPath            string       // workbook path, Downloads/<FileName> when empty
+	FileName            string       // defaults to <RunName>_results.xlsx
+}
+
+// sheet names of known output schemas
+var excelSchemaSheetNames = map[string]string{
+	"sba_bel_summary":  "BEL summary",
+	"liability_output": "Liability output",
+	"debug_info":       "Debug info",
+}
+
+// ExportRunToExcel bundles the selected outputs of a run into a single workbook, one sheet per
+// file, plus a "Config" sheet with the liability config and a "Run info" sheet. Returns the
+// path of the workbook
+func (a *App) ExportRunToExcel(request ExcelExportRequest) (string, error) {
+	destPath, err := excelDestPath(request)
+	if err != nil {
+		return "", err
+	}
+
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	headerStyle, err := workbook.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool)
+	var sheets []OutputTable
+	var sheetNames []string
+	for _, sheet := range request.Sheets {
+		table, err := a.ReadTable(sheet.Path)
+		if err != nil {
+			runtime.LogError(a.ctx, "Error reading "+sheet.Path+": "+err.Error())
+			return "", fmt.Errorf("%s: %w", sheet.Path, err)
+		}
+		if table.RowCount+1 > excelMaxRows {
+			return "", fmt.Errorf("%s has %d rows, more than a worksheet holds", table.Name, table.RowCount)
+		}
+
+		name := sheet.Name
+		if name == "" {
+			name = excelSchemaSheetNames[table.Schema]
+		}
+		if name == "" {
+			name = strings.TrimSuffix(table.Name, filepath.Ext(table.Name))
+		}
+		name = uniqueSheetName(name, used)
+
+		if err := writeTableSheet(workbook, name, table, headerStyle); err != nil {
+			return "", fmt.Errorf("sheet %s: %w", name, err)
+		}
+		sheets = append(sheets, *table)
+		sheetNames = append(sheetNames, name)
+	}
+
+	if request.LiabilityConfigPath != "" {
+		config, err := readLiabilityConfig(request.LiabilityConfigPath)
+		if err != nil {
+			runtime.LogError(a.ctx, "Error reading liability config: "+err.Error())
+			return "", err
+		}
+		rows := [][]interface{}{{"Parameter", "Value"}}
+		rows = append(rows, configRows(config)...)
+		if err := writeRowsSheet(workbook, uniqueSheetName("Config", used), rows, headerStyle); err != nil {
+			return "", err
+		}
+	}
+
+	rows := [][]interface{}{
+		{"Item", "Value"},
+		{"Run name", request.RunName},
+		{"Exported at", time.Now().Format(time.RFC3339)},
+		{"Liability config", request.LiabilityConfigPath},
+		{},
+		{"Sheet", "Source file", "Schema", "Rows", "Columns", "Modified"},
+	}
+	for i, table := range sheets {
+		modified := ""
+		if info, err := os.Stat(table.Path); err == nil {
+			modified = info.ModTime().Format(time.RFC3339)
+		}
+		rows = append(rows, []interface{}{sheetNames[i], table.Path, table.Schema, table.RowCount, len(table.Columns), modified})
+	}
+	if err := writeRowsSheet(workbook, uniqueSheetName("Run info", used), rows, headerStyle); err != nil {
+		return "", err
+	}
+
+	// a new workbook comes with an empty Sheet1
+	if !used["sheet1"] {
+		if err := workbook.DeleteSheet("Sheet1"); err != nil {
+			return "", err
+		}
+	}
+	workbook.SetActiveSheet(0)
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return "", err
+	}
+	if err := workbook.SaveAs(destPath); err != nil {
+		runtime.LogError(a.ctx, "Error writing workbook: "+err.Error())
+		return "", err
+	}
+
+	return destPath, nil
+}
+
+func excelDestPath(request ExcelExportRequest) (string, error) {
+	if request.DestPath != "" {
+		return request.DestPath, nil
+	}
+
+	fileName := request.FileName
+	if fileName == "" {
+		runName := request.RunName
+		if runName == "" {
+			runName = "run"
+		}
+		fileName = runName + "_results.xlsx"
+	}
+	if !strings.EqualFold(filepath.Ext(fileName), ".xlsx") {
+		fileName += ".xlsx"
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "Downloads", fileName), nil
+}
+
+// uniqueSheetName makes name a valid sheet name (no []:*?/\, at most 31 characters) that
+// isn't used yet, and marks it used. sheet names are case insensitive
+func uniqueSheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = "Sheet"
+	}
+
+	truncate := func(s string, n int) string {
+		runes := []rune(s)
+		if len(runes) > n {
+			return string(runes[:n])
+		}
+		return s
+	}
+
+	unique := truncate(name, excelMaxSheetName)
+	for n := 2; used[strings.ToLower(unique)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		unique = truncate(name, excelMaxSheetName-len(suffix)) + suffix
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+// writeTableSheet writes a table with a bold header, numeric columns as numbers
+func writeTableSheet(workbook *excelize.File, name string, table *OutputTable, headerStyle int) error {
+	if _, err := workbook.NewSheet(name); err != nil {
+		return err
+	}
+	stream, err := workbook.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(table.Headers))
+	for i, heading := range table.Headers {
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: heading}
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	for row := 0; row < table.RowCount; row++ {
+		cells := make([]interface{}, len(table.Columns))
+		for col := range table.Columns {
+			column := &table.Columns[col]
+			if column.Type == ColumnTypeNumber {
+				if v := column.Values[row]; !math.IsNaN(v) && !math.IsInf(v, 0) {
+					cells[col] = v
+				}
+				continue
+			}
+			cells[col] = cellString(column, row)
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, row+2)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetRow(cell, cells); err != nil {
+			return err
+		}
+	}
+
+	return stream.Flush()
+}
+
+// writeRowsSheet writes rows as they are, the first row and rows following a blank one in bold
+func writeRowsSheet(workbook *excelize.File, name string, rows [][]interface{}, headerStyle int) error {
+	if _, err := workbook.NewSheet(name); err != nil {
+		return err
+	}
+	stream, err := workbook.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+
+	bold := true
+	for i, row := range rows {
+		cells := row
+		if bold {
+			cells = make([]interface{}, len(row))
+			for j, value := range row {
+				cells[j] = excelize.Cell{StyleID: headerStyle, Value: value}
+			}
+		}
+		bold = len(row) == 0
+
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetRow(cell, cells); err != nil {
+			return err
+		}
+	}
+
+	return stream.Flush()
+}
+
+func readLiabilityConfig(path string) (*LiabilityConfig, error) {
+	var config LiabilityConfig
+	if err := readJSON5File(path, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// configRows lists the fields of a config by their json name, in declaration order.
+// lists and tables are written as JSON
+func configRows(config interface{}) [][]interface{} {
+	value := reflect.Indirect(reflect.ValueOf(config))
+	configType := value.Type()
+
+	var rows [][]interface{}
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+
+		var cell interface{} = value.Field(i).Interface()
+		switch field.Type.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Struct, reflect.Interface, reflect.Ptr:
+			encoded, err := json.Marshal(cell)
+			if err != nil {
+				encoded = []byte(fmt.Sprint(cell))
+			}
+			cell = string(encoded)
+		}
+		rows = append(rows, []interface{}{name, cell})
+	}
+	return rows
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	stdruntime "runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFileCacheMaxEntries = 500
+	defaultFileCacheMaxBytes   = 512 << 20 // 512 MB of parsed cells
+
+	// most files parsed at the same time by ReadFiles
+	maxParallelParses = 8
+)
+
+// FileCacheStats is returned by GetFileCacheStats
+type FileCacheStats struct {
+	Entries    int
+	SizeBytes  int64
+	MaxEntries int
+	MaxBytes   int64
+	Hits       int64
+	Misses     int64
+}
+
+// outputFileCache keeps parsed CSVs in memory, keyed on path and checked against the
+// file's size and mtime, evicting the least recently used files past its limits
+type outputFileCache struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // front is most recently used
+	sizeBytes  int64
+	maxEntries int
+	maxBytes   int64
+	hits       int64
+	misses     int64
+}
+
+type fileCacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+	data    [][]string
+	bytes   int64 // approximate memory held by data
+}
+
+func newOutputFileCache(maxEntries int, maxBytes int64) *outputFileCache {
+	return &outputFileCache{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+	}
+}
+
+// get returns the parsed data of path if it is cached and the file hasn't changed since
+func (c *outputFileCache) get(path string, info os.FileInfo) ([][]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[path]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	entry := element.Value.(*fileCacheEntry)
+	if entry.size != info.Size() || !entry.modTime.Equal(info.ModTime()) {
+		c.remove(element)
+		c.misses++
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	c.hits++
+	return entry.data, true
+}
+
+func (c *outputFileCache) put(path string, info os.FileInfo, data [][]string) {
+	entry := &fileCacheEntry{
+		path:    path,
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		data:    data,
+		bytes:   parsedSize(data),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry.bytes > c.maxBytes {
+		return // would evict everything else, not worth keeping
+	}
+
+	if element, ok := c.entries[path]; ok {
+		c.remove(element)
+	}
+	c.entries[path] = c.lru.PushFront(entry)
+	c.sizeBytes += entry.bytes
+
+	c.evict()
+}
+
+// evict drops least recently used entries until the cache fits its limits
+func (c *outputFileCache) evict() {
+	for c.lru.Len() > 0 && (c.lru.Len() > c.maxEntries || c.sizeBytes > c.maxBytes) {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *outputFileCache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*fileCacheEntry)
+	delete(c.entries, entry.path)
+	c.sizeBytes -= entry.bytes
+}
+
+// invalidate drops path, or every file under it when it is a folder. an empty path clears everything
+func (c *outputFileCache) invalidate(path string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	target := filepath.Clean(path)
+	prefix := target + string(filepath.Separator)
+	for cached, element := range c.entries {
+		cached = filepath.Clean(cached)
+		if path == "" || cached == target || strings.HasPrefix(cached, prefix) {
+			c.remove(element)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (c *outputFileCache) stats() FileCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return FileCacheStats{
+		Entries:    c.lru.Len(),
+		SizeBytes:  c.sizeBytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+		Hits:       c.hits,
+		Misses:     c.misses,
+	}
+}
+
+// parsedSize approximates the memory held by parsed rows (string headers plus contents)
+func parsedSize(data [][]string) int64 {
+	var size int64
+	for _, row := range data {
+		size += 24 + int64(len(row))*16
+		for _, cell := range row {
+			size += int64(len(cell))
+		}
+	}
+	return size
+}
+
+// InvalidateFileCache forgets cached parses of a file, or of every file under a folder.
+// an empty path clears the whole cache. Returns the number of files dropped
+func (a *App) InvalidateFileCache(path string) int {
+	return a.fileCache.invalidate(path)
+}
+
+// GetFileCacheStats returns the current size and hit rate of the parsed file cache
+func (a *App) GetFileCacheStats() FileCacheStats {
+	return a.fileCache.stats()
+}
+
+// SetFileCacheLimits changes how many files and how many MB of parsed data are kept.
+// values <= 0 restore the defaults
+func (a *App) SetFileCacheLimits(maxEntries int, maxMB int) FileCacheStats {
+	if maxEntries <= 0 {
+		maxEntries = defaultFileCacheMaxEntries
+	}
+	maxBytes := int64(defaultFileCacheMaxBytes)
+	if maxMB > 0 {
+		maxBytes = int64(maxMB) << 20
+	}
+
+	a.fileCache.mu.Lock()
+	a.fileCache.maxEntries = maxEntries
+	a.fileCache.maxBytes = maxBytes
+	a.fileCache.evict()
+	a.fileCache.mu.Unlock()
+
+	return a.fileCache.stats()
+}
+
+// parseCSVFileCached parses a CSV (or parquet file), reusing the cached result when the file is unchanged
+func (a *App) parseCSVFileCached(path string) ([][]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, ok := a.fileCache.get(path, info); ok {
+		return data, nil
+	}
+
+	parse := parseCSVFile
+	if strings.EqualFold(filepath.Ext(path), ".parquet") {
+		parse = parseParquetFile
+	}
+	data, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a.fileCache.put(path, info, data)
+	return data, nil
+}
+
+// fileCandidate is a file picked by ReadFiles, waiting to be parsed
+type fileCandidate struct {
+	path string
+	name string
+}
+
+type parsedFile struct {
+	file *CSVFile
+	err  error
+}
+
+// parseFiles parses the candidates with bounded parallelism, keeping their order
+func (a *App) parseFiles(candidates []fileCandidate) []parsedFile {
+	results := make([]parsedFile, len(candidates))
+
+	workers := stdruntime.GOMAXPROCS(0)
+	if workers > maxParallelParses {
+		workers = maxParallelParses
+	}
+	semaphore := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	for i, candidate := range candidates {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, candidate fileCandidate) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			data, err := a.parseCSVFileCached(candidate.path)
+			if err != nil {
+				results[i] = parsedFile{err: err}
+				return
+			}
+			results[i] = parsedFile{file: &CSVFile{Path: candidate.path, Name: candidate.name, Data: data}}
+		}(i, candidate)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var (
+	// file formats read unless ReadFilesOptions.Formats asks for others
+	defaultFileFormats = []string{".csv"}
+
+	// file formats the readers can parse, delimited text and parquet
+	tableFileFormats = []string{".csv", ".tsv", ".txt", ".parquet"}
+)
+
+// ReadFilesOptions selects which files of a folder ReadFilesMatching reads
+type ReadFilesOptions struct {
+	Filter   string   // plain substring the file name must contain, as in ReadFiles
+	Include  []string // file must match one of these when given
+	Exclude  []string // file must match none of these
+	UseRegex bool     // Include/Exclude are regular expressions instead of globs
+	MaxDepth int      // 0 reads only the folder itself, n goes n folders down, -1 has no limit
+	SortBy   string   // name (default) or mtime
+	Desc     bool     // reverse the sort order
+	MaxFiles int      // at most this many files after sorting, 0 for no cap
+	Formats  []string // file extensions to read out of .csv, .tsv, .txt and .parquet, .csv when empty
+}
+
+// ReadFilesMatching reads the CSV files of a folder selected by options, or the delimited and
+// parquet files Formats lists. a pattern matches a file when it matches either its name or its
+// slash separated path relative to the folder. files that fail to parse are logged and skipped
+func (a *App) ReadFilesMatching(path string, options ReadFilesOptions) ([]CSVFile, error) {
+	return a.readFiles(path, options, false)
+}
+
+func (a *App) readFiles(path string, options ReadFilesOptions, stopOnError bool) ([]CSVFile, error) {
+	candidates, err := selectFiles(path, options)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading directory"+err.Error())
+		return nil, err
+	}
+
+	return a.readCandidates(candidates, stopOnError)
+}
+
+// readCandidates parses the selected files, a failure is logged and skipped unless stopOnError
+func (a *App) readCandidates(candidates []fileCandidate, stopOnError bool) ([]CSVFile, error) {
+	var result []CSVFile
+	for i, parsed := range a.parseFiles(candidates) {
+		if parsed.err != nil {
+			runtime.LogErrorf(a.ctx, "Error processing file %s: %v\n", candidates[i].path, parsed.err)
+			if stopOnError {
+				return nil, parsed.err
+			}
+			continue
+		}
+		result = append(result, *parsed.file)
+	}
+
+	return result, nil
+}
+
+// fileMatcher applies the name filter and include/exclude patterns of ReadFilesOptions
+type fileMatcher struct {
+	filter  string
+	formats []string
+	include []func(string) bool
+	exclude []func(string) bool
+}
+
+func newFileMatcher(options ReadFilesOptions) (*fileMatcher, error) {
+	matcher := &fileMatcher{filter: options.Filter, formats: defaultFileFormats}
+
+	if len(options.Formats) > 0 {
+		matcher.formats = nil
+		for _, format := range options.Formats {
+			format = strings.ToLower(strings.TrimSpace(format))
+			if !strings.HasPrefix(format, ".") {
+				format = "." + format
+			}
+			matcher.formats = append(matcher.formats, format)
+		}
+	}
+
+	var err error
+	if matcher.include, err = compilePatterns(options.Include, options.UseRegex); err != nil {
+		return nil, err
+	}
+	if matcher.exclude, err = compilePatterns(options.Exclude, options.UseRegex); err != nil {
+		return nil, err
+	}
+
+	return matcher, nil
+}
+
+func compilePatterns(patterns []string, useRegex bool) ([]func(string) bool, error) {
+	var matchers []func(string) bool
+
+	for _, pattern := range patterns {
+		if useRegex {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+			}
+			matchers = append(matchers, re.MatchString)
+			continue
+		}
+
+		// validate the glob once, filepath.Match only reports bad patterns when matching
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		glob := pattern
+		matchers = append(matchers, func(name string) bool {
+			matched, _ := filepath.Match(glob, name)
+			return matched
+		})
+	}
+
+	return matchers, nil
+}
+
+// matches reports whether a file (name and slash separated path relative to the root) is selected
+func (m *fileMatcher) matches(name string, relPath string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	supported := false
+	for _, format := range m.formats {
+		if ext == format {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return false
+	}
+
+	if m.filter != "" && !strings.Contains(name, m.filter) {
+		return false
+	}
+
+	matchAny := func(matchers []func(string) bool) bool {
+		for _, match := range matchers {
+			if match(name) || match(relPath) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(m.include) > 0 && !matchAny(m.include) {
+		return false
+	}
+	return !matchAny(m.exclude)
+}
+
+// selectFiles lists the files under root picked by options, sorted and capped
+func selectFiles(root string, options ReadFilesOptions) ([]fileCandidate, error) {
+	matcher, err := newFileMatcher(options)
+	if err != nil {
+		return nil, err
+	}
+
+	type selected struct {
+		candidate fileCandidate
+		modTime   int64
+	}
+	var files []selected
+
+	err = filepath.WalkDir(root, func(fPath string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, relErr := filepath.Rel(root, fPath)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			// Skip the root directory itself, stop descending past MaxDepth
+			if rel == "." {
+				return nil
+			}
+			if options.MaxDepth >= 0 && strings.Count(rel, "/")+1 > options.MaxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !matcher.matches(d.Name(), rel) {
+			return nil
+		}
+
+		file := selected{candidate: fileCandidate{path: fPath, name: d.Name()}}
+		if options.SortBy == "mtime" {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			file.modTime = info.ModTime().UnixNano()
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch options.SortBy {
+	case "", "name":
+		sort.SliceStable(files, func(i, j int) bool { return files[i].candidate.path < files[j].candidate.path })
+	case "mtime":
+		sort.SliceStable(files, func(i, j int) bool { return files[i].modTime < files[j].modTime })
+	default:
+		return nil, fmt.Errorf("unknown sort order %q", options.SortBy)
+	}
+	if options.Desc {
+		for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+			files[i], files[j] = files[j], files[i]
+		}
+	}
+
+	if options.MaxFiles > 0 && len(files) > options.MaxFiles {
+		files = files[:options.MaxFiles]
+	}
+
+	candidates := make([]fileCandidate, len(files))
+	for i, file := range files {
+		candidates[i] = file.candidate
+	}
+	return candidates, nil
+}
+
+// detectFileDelimiter picks the delimiter of a text file from its first line: the most
+// frequent of comma, semicolon, tab and pipe outside quotes. defaults to comma
+func detectFileDelimiter(path string) (rune, error) {
+	file, err := openTextFile(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReaderSize(file, 64*1024).ReadString('\n')
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return 0, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		return '\t', nil
+	}
+	return detectDelimiter(line), nil
+}
+
+func detectDelimiter(line string) rune {
+	counts := map[rune]int{}
+	inQuotes := false
+	for _, r := range line {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ',', ';', '\t', '|':
+			if !inQuotes {
+				counts[r]++
+			}
+		}
+	}
+
+	delimiter := ','
+	for _, candidate := range []rune{';', '\t', '|'} {
+		if counts[candidate] > counts[delimiter] {
+			delimiter = candidate
+		}
+	}
+	return delimiter
+}
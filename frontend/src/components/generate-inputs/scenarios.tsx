@@ -1,9 +1,12 @@
 import { useState, useEffect } from "react";
-import { useUIConfigStore } from "../../stores";
+import { useLiabilityConfigStore, useUIConfigStore } from "../../stores";
 import { main } from "../../../wailsjs/go/models";
 import {
-  ExecutePythonScript,
+  GenerateScenarios,
+  GenerateStochasticScenarios,
   GetFilenames,
+  ImportSpotRates,
+  OpenFileDialog,
   ReadScenarioConfig,
   WriteJsonFile,
 } from "../../../wailsjs/go/main/App";
@@ -20,7 +23,7 @@ import {
 } from "@headlessui/react";
 import { Button } from "../ui/Button";
 import { useAutoAnimate } from "@formkit/auto-animate/react";
-import { normalizePathString } from "../../utils/output";
+import { EventsOn } from "../../../wailsjs/runtime";
 
 // main.ScenarioConfig is our type
 
@@ -32,9 +35,43 @@ interface InputScenarioConfig extends main.ScenarioConfig {
   }[];
 }
 
+// emitted by GenerateScenarios as "scenarioProgress"
+interface ScenarioProgress {
+  RunID: string;
+  Stage: string;
+  Done: number;
+  Total: number;
+  Message: string;
+}
+
 const extrapolationOptions = [
   { name: "Flat", id: 0 },
   { name: "FlatForward", id: 1 },
+  { name: "SmithWilson", id: 2 },
+];
+
+// Hull-White one-factor inputs, used instead of the shocks when stochastic is on
+const stochasticInputs: {
+  name:
+    | "MeanReversion"
+    | "Volatility"
+    | "Scenarios"
+    | "InnerScenarios"
+    | "Seed"
+    | "OuterTemplatePath"
+    | "InnerTemplatePath";
+  label: string;
+  id: number;
+}[] = [
+  { name: "MeanReversion", label: "Mean Reversion", id: 0 },
+  { name: "Volatility", label: "Volatility", id: 1 },
+  { name: "Scenarios", label: "Outer Scenarios", id: 2 },
+  { name: "InnerScenarios", label: "Inner Scenarios", id: 3 },
+  { name: "Seed", label: "Seed", id: 4 },
+  // an existing pALM scenario file whose columns and units the generated files copy
+  // the liability config's sOutterLoopScenario / sInnerLoopScenario files when empty
+  { name: "OuterTemplatePath", label: "Outer Template File (optional)", id: 5 },
+  { name: "InnerTemplatePath", label: "Inner Template File (optional)", id: 6 },
 ];
 
 const UFRInputs: {
@@ -51,8 +88,8 @@ const UFRInputs: {
 
 export const Scenarios: React.FC = () => {
   const { config } = useUIConfigStore();
-  const { baseScenarioConfigPath, pythonGenerateScenarioScript, scenarioConfigsPath } =
-    config;
+  const { baseScenarioConfigPath, scenarioConfigsPath, palmFolderPath } = config;
+  const { configPath: liabilityConfigPath } = useLiabilityConfigStore();
 
   const [parent] = useAutoAnimate();
   const [parent2] = useAutoAnimate();
@@ -70,6 +107,23 @@ export const Scenarios: React.FC = () => {
   const [isGeneratingScenarios, setIsGeneratingScenarios] = useState<boolean>(false);
   const [error, setError] = useState<string | null>(null);
   const [isCompleted, setIsCompleted] = useState<boolean>(false);
+  const [progress, setProgress] = useState<ScenarioProgress | null>(null);
+  const [outputHash, setOutputHash] = useState<string>("");
+  const [importMessages, setImportMessages] = useState<string[]>([]);
+  const [isStochastic, setIsStochastic] = useState<boolean>(false);
+  const [stochastic, setStochastic] = useState<Record<string, string>>({
+    MeanReversion: "0.05",
+    Volatility: "0.01",
+    Scenarios: "1000",
+    InnerScenarios: "0",
+    Seed: "1",
+    OuterTemplatePath: "",
+    InnerTemplatePath: "",
+  });
+
+  useEffect(() => {
+    return EventsOn("scenarioProgress", (data: ScenarioProgress) => setProgress(data));
+  }, []);
 
   useEffect(() => {
     const loadScenarioConfig = async () => {
@@ -155,11 +209,60 @@ export const Scenarios: React.FC = () => {
     setSpotRatesInput(copy);
   };
 
+  // fills the SOFR or TR column from a curve file, picking the curve of Asof
+  const handleImportSpotRates = async (curve: "SOFR" | "TR") => {
+    try {
+      const path = await OpenFileDialog({ DefaultDirectory: "", SelectDirectory: false });
+      if (!path || !scenarioConfig) return;
+
+      const key = curve === "SOFR" ? "sofr" : "tr";
+      const current = spotRatesInput.map((item) => parseFloat(item[key]));
+      const unit = current.some((x) => Math.abs(x) > 0.3) ? "percent" : "decimal";
+
+      const result = await ImportSpotRates({
+        Paths: [path],
+        Asof: scenarioConfig.Asof,
+        Curve: curve,
+        Tenors: spotRatesInput.map((item) => item.duration).filter((x) => x !== ""),
+        Unit: spotRatesInput.length > 0 ? unit : "",
+        InputUnit: "",
+      });
+
+      if (spotRatesInput.length === 0) {
+        setSpotRatesInput(
+          Object.entries(result.Rates).map(([duration, rate]) => ({
+            duration,
+            sofr: curve === "SOFR" ? rate.toString() : "0",
+            tr: curve === "TR" ? rate.toString() : "0",
+          }))
+        );
+      } else {
+        setSpotRatesInput((prev) =>
+          prev.map((item) =>
+            item.duration in result.Rates
+              ? { ...item, [key]: result.Rates[item.duration].toString() }
+              : item
+          )
+        );
+      }
+
+      const messages = [...result.Warnings];
+      if (result.Missing.length > 0) {
+        messages.push(`${curve}: no rate for ${result.Missing.join(", ")}`);
+      }
+      setImportMessages([`${curve} curve of ${result.CurveDate || scenarioConfig.Asof}`, ...messages]);
+    } catch (err) {
+      setImportMessages([err as string]);
+    }
+  };
+
   const handleGenerateScenarios = async () => {
     try {
       setIsGeneratingScenarios(true);
       setError(null);
       setIsCompleted(false);
+      setProgress(null);
+      setOutputHash("");
 
       if (!scenarioConfig) {
         setError("No config file found");
@@ -195,11 +298,25 @@ export const Scenarios: React.FC = () => {
         JSON.stringify(completedScenarioConfig)
       );
 
-      // creating scenario files with python script
-      await ExecutePythonScript(normalizePathString(pythonGenerateScenarioScript), [
-        newConfigFileName,
-      ]);
-
+      // creating scenario files, with ESG_deterministic.py when usePythonGenerator is set
+      const configPath = `${scenarioConfigsPath}/${newConfigFileName}`;
+      const result = isStochastic
+        ? await GenerateStochasticScenarios({
+            ConfigPath: configPath,
+            MeanReversion: parseFloat(stochastic.MeanReversion),
+            Volatility: parseFloat(stochastic.Volatility),
+            Scenarios: parseInt(stochastic.Scenarios),
+            InnerScenarios: parseInt(stochastic.InnerScenarios) || 0,
+            Seed: parseInt(stochastic.Seed) || 0,
+            Antithetic: false,
+            OuterTemplatePath: stochastic.OuterTemplatePath,
+            InnerTemplatePath: stochastic.InnerTemplatePath,
+            LiabilityConfigPath: liabilityConfigPath,
+            PalmFolderPath: palmFolderPath,
+          })
+        : await GenerateScenarios(configPath);
+
+      setOutputHash(result.Hash);
       setIsCompleted(true);
     } catch (err) {
       setError(err as string);
@@ -272,7 +389,8 @@ export const Scenarios: React.FC = () => {
           </Listbox>
         </div>
 
-        {scenarioConfig?.Extrapolation === "FlatForward" &&
+        {(scenarioConfig?.Extrapolation === "FlatForward" ||
+          scenarioConfig?.Extrapolation === "SmithWilson") &&
           [...UFRInputs].map((item) => (
             <div className="flex flex-col gap-y-2" key={item.id}>
               <p className="text-sm/6 text-white font-medium">{item.name}</p>
@@ -303,6 +421,33 @@ export const Scenarios: React.FC = () => {
           />
         </div>
 
+        <div className="flex items-center gap-x-2">
+          <input
+            type="checkbox"
+            checked={isStochastic}
+            onChange={(e) => setIsStochastic(e.currentTarget.checked)}
+          />
+          <p className="text-sm/6 text-white font-medium">Stochastic (Hull-White)</p>
+        </div>
+
+        {isStochastic &&
+          stochasticInputs.map((item) => (
+            <div className="flex flex-col gap-y-2" key={item.id}>
+              <p className="text-sm/6 text-white font-medium">{item.label}</p>
+              <Input
+                value={stochastic[item.name]}
+                onChange={(e) => {
+                  const value = e.currentTarget.value;
+                  setStochastic((prev) => ({ ...prev, [item.name]: value }));
+                }}
+                className={cn(
+                  "w-full block rounded-lg border border-dark-600 bg-dark-800 py-1.5 px-3 text-sm/6 text-white pointer-events-auto",
+                  "focus:outline-none data-[focus]:outline-2 data-[focus]:-outline-offset-2 data-[focus]:outline-white/25 disabled:opacity-80"
+                )}
+              />
+            </div>
+          ))}
+
         <div className="flex flex-col gap-y-2">
           <p className="text-sm/6 text-white font-medium">Tenor Spot Rates</p>
           <div className="border rounded-lg border-dark-600 overflow-hidden">
@@ -366,6 +511,15 @@ export const Scenarios: React.FC = () => {
           <div className="w-full flex justify-center cursor-pointer">
             <Plus className="w-5 h-5" onClick={handleAddSpotRateItem} />
           </div>
+          <div className="flex w-full justify-center gap-x-2">
+            <Button onClick={() => handleImportSpotRates("SOFR")}>Import SOFR</Button>
+            <Button onClick={() => handleImportSpotRates("TR")}>Import TR</Button>
+          </div>
+          {importMessages.map((message, i) => (
+            <p key={i} className="text-xs/6 text-white/60">
+              {message}
+            </p>
+          ))}
         </div>
       </div>
 
@@ -374,8 +528,16 @@ export const Scenarios: React.FC = () => {
           {isGeneratingScenarios ? "Generating" : "Generate Scenarios"}
         </Button>
         {isGeneratingScenarios && <LoadingIcon />}
+        {isGeneratingScenarios && progress && progress.Total > 0 && (
+          <p className="text-sm/6 text-white/60">
+            {progress.Done}/{progress.Total} {progress.Message}
+          </p>
+        )}
         {isCompleted && <Check color="green" size={30} className="" />}
       </div>
+      {isCompleted && outputHash && (
+        <p className="text-xs/6 text-white/60 text-center mt-2">Output hash: {outputHash}</p>
+      )}
     </div>
   );
 };
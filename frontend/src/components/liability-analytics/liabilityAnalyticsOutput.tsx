@@ -1,7 +1,7 @@
 import { useState, useEffect } from "react";
 import { Listbox, ListboxButton, ListboxOption, ListboxOptions } from "@headlessui/react";
 import * as dfd from "danfojs/dist/danfojs-browser/src";
-import { ReadFiles } from "../../../wailsjs/go/main/App";
+import { ReadRunFiles } from "../../../wailsjs/go/main/App";
 
 import { cn } from "../../utils/utils";
 import { ChevronDown } from "lucide-react";
@@ -39,10 +39,10 @@ export const LiabilityAnalyticsOutput: React.FC<{
       setIsLoading(true);
       setError(null);
 
-      const csvData = await ReadFiles(
+      // the files listed in the run's output manifest, or the folder's when it has none
+      const csvData = await ReadRunFiles(
         exportFolderPath,
-        "Parsed_LiabilityOutput_Scenario",
-        false
+        "Parsed_LiabilityOutput_Scenario"
       );
 
       if (csvData) {
@@ -1,6 +1,6 @@
 import { useState, useEffect } from "react";
 import { Listbox, ListboxButton, ListboxOption, ListboxOptions } from "@headlessui/react";
-import { ReadFiles } from "../../../wailsjs/go/main/App";
+import { ReadRunFiles } from "../../../wailsjs/go/main/App";
 
 import { cn } from "../../utils/utils";
 import { ChevronDown } from "lucide-react";
@@ -37,7 +37,8 @@ export const RiskOutput: React.FC<{
       setIsLoading(true);
       setError(null);
 
-      const csvData = await ReadFiles(exportFolderPath, "Parsed_Scenario", false);
+      // the files listed in the run's output manifest, or the folder's when it has none
+      const csvData = await ReadRunFiles(exportFolderPath, "Parsed_Scenario");
 
       if (csvData) {
         const options: Option[] = csvData.map((x, i) => ({
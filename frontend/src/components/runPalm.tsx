@@ -2,16 +2,21 @@ import { useState, useEffect, useRef } from "react";
 import {
   ExecutePalm,
   GetFilenames,
+  PreflightScenarioFiles,
+  ValidateSAATargetPort,
   WriteJsonFile,
-  ExecutePythonScript,
+  PostProcessRun,
+  RunOutputPath,
+  WriteOutputManifest,
 } from "../../wailsjs/go/main/App";
 import { EventsOn } from "../../wailsjs/runtime";
+import { main } from "../../wailsjs/go/models";
 
 import { Button } from "./ui/Button";
 import { LoadingIcon } from "./svgs/LoadingIcon";
 import { Check, CircleX, X } from "lucide-react";
-import { getRelativePath, getTraversalPathToFolder, resolvePath } from "../utils/output";
-import { useLiabilityConfigStore, useUIConfigStore } from "../stores";
+import { getTraversalPathToFolder, resolvePath } from "../utils/output";
+import { useLiabilityConfigStore } from "../stores";
 import { TextEffect } from "./ui/motion-ui/text-effect";
 
 export const RunPalm: React.FC<{
@@ -21,7 +26,6 @@ export const RunPalm: React.FC<{
   isDisabled?: boolean;
 }> = ({ palmFolderPath, palmConfigPath, moduleType, isDisabled }) => {
   const { config, configPath } = useLiabilityConfigStore();
-  const { config: uiConfig } = useUIConfigStore();
 
   const [isLoading, setIsLoading] = useState<boolean>(false);
   const [hadError, setHadError] = useState(false);
@@ -68,8 +72,37 @@ export const RunPalm: React.FC<{
       setErrors([]);
       setHadError(false);
 
+      // pALM only fails late on scenario files that are too short, check them first
+      const preflight = await PreflightScenarioFiles(
+        config as main.LiabilityConfig,
+        palmFolderPath
+      );
+      const issues = preflight.Issues.map(
+        (issue) => `${issue.Severity}: ${issue.Field} (${issue.Path}): ${issue.Message}`
+      );
+      if (!preflight.Passed) {
+        setHadError(true);
+        setErrors(issues);
+        return;
+      }
+      setOutput((prev) => [...prev, ...issues]);
+
+      // report problems of the SAA target weights, pALM decides whether they stop the run
+      if (moduleType === "saa") {
+        const saaReport = await ValidateSAATargetPort({
+          Config: config as main.LiabilityConfig,
+          Bounds: [],
+          Tolerance: 0,
+          Normalise: false,
+        });
+        const saaIssues = saaReport.Issues.map(
+          (issue) =>
+            `${issue.Severity}: ${issue.Portfolio} period ${issue.Period} ${issue.AssetClass}: ${issue.Message}`
+        );
+        setOutput((prev) => [...prev, ...saaIssues]);
+      }
+
       const pathToConfig = getTraversalPathToFolder(palmFolderPath, palmConfigPath);
-      const updatedPath = ensurePalmLauncherPath(palmFolderPath);
 
       // grab all filenames inside the config folder
       const fileNames = await GetFilenames(palmConfigPath);
@@ -79,8 +112,19 @@ export const RunPalm: React.FC<{
       // writing new liability_config.json file
       await WriteJsonFile(newConfigPath, JSON.stringify(config, null, 2));
 
+      // files modified from here on are the run's, other runs share the output folder
+      const runStartedAt = new Date().toISOString();
+
       if (pathToConfig) {
-        await ExecutePalm(updatedPath, pathToConfig, newConfigFileName);
+        // launcher (pALMLauncher.exe, pALMLauncher or dotnet pALMLauncher.dll) is resolved in Go
+        await ExecutePalm(palmFolderPath, pathToConfig, newConfigFileName);
+      }
+
+      // record the files the run produced, post processing finds the engine outputs through it
+      const outputFolder =
+        palmFolderPath && config.sCashPath ? resolvePath(palmFolderPath, config.sCashPath) : "";
+      if (outputFolder) {
+        await WriteOutputManifest(outputFolder, config.sFileName ?? "", runStartedAt);
       }
 
       if (
@@ -88,41 +132,19 @@ export const RunPalm: React.FC<{
         ["valuation", "liability_analytics", "risk_analytics", "saa"].includes(
           moduleType
         ) &&
-        palmFolderPath
+        outputFolder
       ) {
-        const runName = config.sFileName;
-        const relativeOutputFolderToPalmFolder = config.sCashPath;
-
-        const outputFilePath = resolvePath(
-          palmFolderPath,
-          moduleType === "valuation"
-            ? `${relativeOutputFolderToPalmFolder}` // for valuation
-            : moduleType === "liability_analytics"
-            ? `${relativeOutputFolderToPalmFolder}${runName}_LiabilityOutput_Scenario_0.csv` // for liability_analytics
-            : moduleType === "risk_analytics"
-            ? `${relativeOutputFolderToPalmFolder}DebugInfo_Scenario_${runName}_0.csv` // for risk_analytics
-            : moduleType === "saa"
-            ? `${relativeOutputFolderToPalmFolder}` // for saa
-            : ""
-        );
+        const runName = config.sFileName ?? "";
 
-        // run script with this path
-        const relativeOutputFileToPythonScript = getRelativePath(
-          uiConfig.scriptsFolderPath,
-          outputFilePath
-        );
+        // the folder for valuation and saa, the scenario 0 file for liability and risk analytics
+        const outputFilePath = await RunOutputPath(moduleType, outputFolder, runName);
+
+        // builds the Parsed_* / Combined_Scenario_* files with the built in parser,
+        // or resultMergeScenarios.py when usePythonParser is set in the ui config
+        await PostProcessRun(moduleType, outputFilePath, runName);
 
-        // console.log("script path:", uiConfig.pythonParserScript);
-        // console.log("param 1 - module type:", moduleType);
-        // console.log("param 2 - output file path:", relativeOutputFileToPythonScript);
-        // console.log("param 3 - run name or run id:", runName);
-
-        // executing python script
-        await ExecutePythonScript(uiConfig.pythonParserScript, [
-          moduleType,
-          relativeOutputFileToPythonScript,
-          runName ?? "",
-        ]);
+        // and records the post processed files as well
+        await WriteOutputManifest(outputFolder, runName, runStartedAt);
       }
     } catch (err) {
       console.error(err);
@@ -178,19 +200,6 @@ export const RunPalm: React.FC<{
   );
 };
 
-const ensurePalmLauncherPath = (path: string): string => {
-  const launcher = "pALMLauncher.exe";
-
-  // Check if the path already ends with "/pALMLauncher.exe"
-  if (!path.endsWith(launcher)) {
-    // If not, append it to the path
-    return `${path}${path.endsWith("/") ? "" : "/"}${launcher}`;
-  }
-
-  // Return the path unchanged if it already ends with "/pALMLauncher.exe"
-  return path;
-};
-
 const getNextLiabilityConfigVersion = (fileNames: string[]): string => {
   const baseName = "liability_config";
   const extension = ".json";
@@ -1,5 +1,5 @@
 import { useState, useEffect } from "react";
-import { ReadFiles } from "../../../wailsjs/go/main/App";
+import { ReadRunFiles } from "../../../wailsjs/go/main/App";
 import useCSVDownloader from "../../hooks/useCSVDownloader";
 import { Listbox, ListboxButton, ListboxOption, ListboxOptions } from "@headlessui/react";
 
@@ -32,7 +32,8 @@ export const SAAOutput: React.FC<{
     try {
       setIsLoading(true);
 
-      const csvData = await ReadFiles(exportFolderPath, "Parsed_", false);
+      // the files listed in the run's output manifest, or the folder's when it has none
+      const csvData = await ReadRunFiles(exportFolderPath, "Parsed_");
       console.log(csvData);
 
       if (csvData) {
@@ -1,5 +1,11 @@
 import { useState, useEffect } from "react";
-import { OpenFileDialog } from "../../../wailsjs/go/main/App";
+import {
+  ExportSAATargetPort,
+  ImportSAATargetPort,
+  OpenFileDialog,
+} from "../../../wailsjs/go/main/App";
+import { main } from "../../../wailsjs/go/models";
+import { Button } from "../ui/Button";
 import {
   Input,
   Listbox,
@@ -102,6 +108,7 @@ export const SAASettings: React.FC<{
   const [isLoading, setIsLoading] = useState(false);
   const [selectedConfig, setSelectedConfig] = useState<ConfigOption>(configOptions[0]);
   const [scenarioFolderPath, setScenarioFolderPath] = useState<string>("");
+  const [targetPortMessages, setTargetPortMessages] = useState<string[]>([]);
 
   useEffect(() => {
     if (configOptions.length > 0) {
@@ -179,6 +186,60 @@ export const SAASettings: React.FC<{
     }
   };
 
+  // SAA_target_port(_inner) as CSV, a row per period and a column per asset class
+  const handleExportTargetPort = async () => {
+    try {
+      const folder = await OpenFileDialog({ DefaultDirectory: "", SelectDirectory: true });
+      if (!folder) return;
+      await ExportSAATargetPort(config as main.LiabilityConfig, "outer", `${folder}/SAA_target_port.csv`);
+      await ExportSAATargetPort(
+        config as main.LiabilityConfig,
+        "inner",
+        `${folder}/SAA_target_port_inner.csv`
+      );
+      setTargetPortMessages([`Exported to ${folder}`]);
+    } catch (err) {
+      setTargetPortMessages([err as string]);
+    }
+  };
+
+  // imported weights are checked and saved as the next liability_config version
+  const handleImportTargetPort = async (portfolio: "outer" | "inner") => {
+    try {
+      const path = await OpenFileDialog({ DefaultDirectory: "", SelectDirectory: false });
+      if (!path) return;
+      const result = await ImportSAATargetPort({
+        Config: config as main.LiabilityConfig,
+        ConfigFolder: configPath,
+        OuterPath: portfolio === "outer" ? path : "",
+        InnerPath: portfolio === "inner" ? path : "",
+        Bounds: [],
+        Normalise: false,
+      });
+
+      const messages = result.Files.flatMap((file) => [
+        ...(file.UnknownColumns.length > 0
+          ? [`Unknown columns ignored: ${file.UnknownColumns.join(", ")}`]
+          : []),
+        ...(file.MissingColumns.length > 0
+          ? [`Missing asset classes set to 0: ${file.MissingColumns.join(", ")}`]
+          : []),
+      ]);
+      const issues = result.Report.Issues.map(
+        (issue) =>
+          `${issue.Severity}: ${issue.Portfolio} period ${issue.Period} ${issue.AssetClass}: ${issue.Message}`
+      );
+      if (result.Passed) {
+        setConfig(result.Config);
+        setTargetPortMessages([`Saved as ${result.ConfigPath}`, ...messages, ...issues]);
+      } else {
+        setTargetPortMessages(["Not imported", ...messages, ...issues]);
+      }
+    } catch (err) {
+      setTargetPortMessages([err as string]);
+    }
+  };
+
   return (
     <div className="w-full flex flex-col">
       <h1 className="text-xl font-semibold">Settings</h1>
@@ -300,6 +361,20 @@ export const SAASettings: React.FC<{
             </div>
           </div>
         )}
+
+        <div className="flex flex-col w-full gap-y-2">
+          <p className="text-sm/6 text-white font-medium">Target Portfolio</p>
+          <div className="flex gap-x-2">
+            <Button onClick={() => handleImportTargetPort("outer")}>Import Outer</Button>
+            <Button onClick={() => handleImportTargetPort("inner")}>Import Inner</Button>
+            <Button onClick={handleExportTargetPort}>Export</Button>
+          </div>
+          {targetPortMessages.map((message, i) => (
+            <p key={i} className="text-xs/6 text-white/60">
+              {message}
+            </p>
+          ))}
+        </div>
       </div>
     </div>
   );
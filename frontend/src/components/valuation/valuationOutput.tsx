@@ -1,5 +1,11 @@
 import { useState, useEffect } from "react";
-import { CopyFileToDownloads, OpenFile, ReadFiles } from "../../../wailsjs/go/main/App";
+import {
+  CopyFileToDownloads,
+  OpenFile,
+  ReadOutputManifest,
+  ReadRunTables,
+} from "../../../wailsjs/go/main/App";
+import { main } from "../../../wailsjs/go/models";
 import { Listbox, ListboxButton, ListboxOption, ListboxOptions } from "@headlessui/react";
 
 import { cn } from "../../utils/utils";
@@ -11,8 +17,7 @@ import { toast, Toaster } from "sonner";
 type Option = {
   id: number;
   name: string;
-  data: string[];
-  initData: string[][];
+  data: (number | null)[];
 };
 
 type EquityOption = {
@@ -35,6 +40,7 @@ export const ValuationOutput: React.FC<{
   const [error, setError] = useState<string | null>(null);
 
   const [fileOptions, setFileOptions] = useState<Option[]>([]);
+  const [manifest, setManifest] = useState<main.OutputManifest | null>(null);
   const [selectedFile, setSelectedFile] = useState<Option>();
 
   const [downloadType, setDownloadType] = useState<EquityOption>(downloadTypeOptions[0]);
@@ -50,14 +56,16 @@ export const ValuationOutput: React.FC<{
   const readFiles = async (exportFolderPath: string) => {
     try {
       setIsLoading(true);
-      const csvDataFiles = await ReadFiles(exportFolderPath, "SBA_without_Equity", false);
+      // the files listed in the run's output manifest, or the folder's when it has none
+      const tables = await ReadRunTables(exportFolderPath, "SBA_without_Equity");
+      setManifest(await ReadOutputManifest(exportFolderPath).catch(() => null));
 
-      if (csvDataFiles) {
-        const options: Option[] = csvDataFiles.map((x, i) => ({
+      if (tables) {
+        const options: Option[] = tables.map((x, i) => ({
           id: i,
           name: x.Name,
-          data: x.Data[1] ? x.Data[1] : [], // data is in second row of csv file
-          initData: x.Data,
+          // one BEL value per column, blanks come back as null
+          data: (x.Columns ?? []).map((column) => column.Values?.[0] ?? null),
         }));
         setFileOptions(options);
       }
@@ -83,7 +91,15 @@ export const ValuationOutput: React.FC<{
       const fileName = `Combined_Scenario_${selectedOutputFileName}_nestedinner_${
         downloadType.name === "Equity" ? "equity" : "noequity"
       }.csv`;
-      const srcFilePath = `${config.palmOutputDataPath}/valuation/${fileName}`;
+      // taken from the run's output manifest, or the valuation output folder when there's none
+      let srcFilePath = `${config.palmOutputDataPath}/valuation/${fileName}`;
+      if (manifest) {
+        const manifestFile = manifest.Files?.find((x) => x.Name === fileName);
+        if (!manifestFile) {
+          throw new Error(`${fileName} is not in the run's output manifest`);
+        }
+        srcFilePath = `${exportFolderPath}/${manifestFile.Path}`;
+      }
 
       // copies selected file to download folder
       const newPath = await CopyFileToDownloads(srcFilePath, fileName);
@@ -232,7 +248,7 @@ export const ValuationOutput: React.FC<{
                 <tr>
                   {data.map((x, i) => (
                     <td key={i} className={cn("px-6 py-4 whitespace-nowrap bg-dark-700")}>
-                      {Math.round(parseFloat(x)).toLocaleString()}
+                      {x === null ? "" : Math.round(x).toLocaleString()}
                     </td>
                   ))}
                 </tr>
@@ -27,10 +27,19 @@ const useUIConfigStore = create<UIConfigStore>((set) => ({
     pythonParserScript: "", // new script to parse output after pALM is ran
     pythonLiabilityConfigScript: "", // script to create liability configs
     pythonSpreadAssumptionScript: "", // script to create spread assumptions
+    usePythonParser: false, // run pythonParserScript instead of the built in post processing
 
     baseScenarioConfigPath: "", // base scenario ESG config file
     scenarioConfigsPath: "", // directory where the ESG config files live
     pythonGenerateScenarioScript: "", // script to create scenario files from scenario config
+    usePythonGenerator: false, // run pythonGenerateScenarioScript instead of the built in generator
+
+    pythonInterpreterPath: "", // explicit python executable, discovered on PATH when all are empty
+    pythonVenvPath: "", // virtualenv directory
+    pythonCondaEnv: "", // conda env name or prefix
+    pythonScriptInterpreters: {}, // per-script overrides keyed by script file name
+
+    regressionSuitePath: "", // regression baselines and run history, ./regression when empty
   },
   setConfig: (uiConfig) =>
     set((state) => ({
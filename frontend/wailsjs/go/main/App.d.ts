@@ -2,24 +2,116 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function AggregateOutput(arg1:main.AggregationRequest):Promise<main.AggregationResult>;
+
+export function ArchiveScenarioConfig(arg1:string,arg2:boolean):Promise<main.ScenarioConfigEntry>;
+
+export function BuildCurve(arg1:main.ScenarioConfig):Promise<main.ScenarioCurves>;
+
+export function CloneScenarioConfig(arg1:string,arg2:string):Promise<main.ScenarioConfigEntry>;
+
+export function CompareOutputs(arg1:string,arg2:string,arg3:main.CompareOptions):Promise<main.OutputComparison>;
+
 export function CopyFileToDownloads(arg1:string,arg2:string):Promise<string>;
 
+export function DeleteRegressionBaseline(arg1:string):Promise<void>;
+
+export function DeleteScenarioConfig(arg1:string,arg2:boolean):Promise<void>;
+
+export function DescribeCSV(arg1:string):Promise<main.CSVDescription>;
+
+export function DescribeScenarioConfig(arg1:string):Promise<main.ScenarioConfigDetails>;
+
 export function ExecutePalm(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function ExecutePythonScript(arg1:string,arg2:Array<string>):Promise<string>;
 
+export function ExportRunToExcel(arg1:main.ExcelExportRequest):Promise<string>;
+
+export function ExportRunToParquet(arg1:string,arg2:string):Promise<main.ParquetExport>;
+
+export function ExportSAATargetPort(arg1:main.LiabilityConfig,arg2:string,arg3:string):Promise<void>;
+
+export function GenerateScenarios(arg1:string):Promise<main.ScenarioGenerationResult>;
+
+export function GenerateStochasticScenarios(arg1:main.StochasticScenarioRequest):Promise<main.ScenarioGenerationResult>;
+
+export function GetFileCacheStats():Promise<main.FileCacheStats>;
+
 export function GetFilenames(arg1:string):Promise<Array<string>>;
 
 export function GetLiabilityConfigs(arg1:string):Promise<Array<main.LiabilityConfigData>>;
 
+export function GetPythonInvocations():Promise<Array<main.PythonInvocation>>;
+
+export function GetRegressionReport(arg1:string):Promise<main.RegressionReport>;
+
+export function GetRegressionReports():Promise<Array<main.RegressionReport>>;
+
+export function ImportSAATargetPort(arg1:main.SAAImportRequest):Promise<main.SAAImportResult>;
+
+export function ImportSpotRates(arg1:main.SpotRateImportRequest):Promise<main.SpotRateImport>;
+
+export function InvalidateFileCache(arg1:string):Promise<number>;
+
+export function ListRegressionBaselines():Promise<Array<main.RegressionBaseline>>;
+
+export function ListScenarioConfigs(arg1:string,arg2:boolean):Promise<Array<main.ScenarioConfigEntry>>;
+
+export function ListScenarios(arg1:string):Promise<main.ScenarioFileSummary>;
+
 export function OpenFile(arg1:string):Promise<void>;
 
 export function OpenFileDialog(arg1:main.FileDialogOptions):Promise<string>;
 
+export function PostProcessRun(arg1:string,arg2:string,arg3:string):Promise<main.PostProcessResult>;
+
+export function PreflightScenarioFiles(arg1:main.LiabilityConfig,arg2:string):Promise<main.PreflightReport>;
+
+export function ReadCSVPage(arg1:string,arg2:number,arg3:number,arg4:Array<string>):Promise<main.CSVPage>;
+
 export function ReadFiles(arg1:string,arg2:string,arg3:boolean):Promise<Array<main.CSVFile>>;
 
+export function ReadFilesMatching(arg1:string,arg2:main.ReadFilesOptions):Promise<Array<main.CSVFile>>;
+
+export function ReadOutputManifest(arg1:string):Promise<main.OutputManifest>;
+
+export function ReadRunFiles(arg1:string,arg2:string):Promise<Array<main.CSVFile>>;
+
+export function ReadRunTables(arg1:string,arg2:string):Promise<Array<main.OutputTable>>;
+
 export function ReadScenarioConfig(arg1:string):Promise<main.ScenarioConfig>;
 
+export function ReadScenarioPaths(arg1:main.ScenarioPathRequest):Promise<main.ScenarioPaths>;
+
+export function ReadShockDefinitions(arg1:string):Promise<main.ShockSet>;
+
+export function ReadTable(arg1:string):Promise<main.OutputTable>;
+
+export function ReadTables(arg1:string,arg2:string,arg3:boolean):Promise<Array<main.OutputTable>>;
+
 export function ReadUIConfig():Promise<main.Config>;
 
+export function RegisterRegressionBaseline(arg1:main.RegisterBaselineRequest):Promise<main.RegressionBaseline>;
+
+export function RenameScenarioConfig(arg1:string,arg2:string):Promise<main.ScenarioConfigEntry>;
+
+export function ResolvePalmLauncher(arg1:string):Promise<main.PalmLauncher>;
+
+export function ResolvePythonInterpreter(arg1:string):Promise<main.PythonInterpreter>;
+
+export function RunOutputPath(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function RunRegressionSuite(arg1:main.RegressionRunRequest):Promise<main.RegressionReport>;
+
+export function ScenarioStatistics(arg1:main.ScenarioStatsRequest):Promise<main.ScenarioDistribution>;
+
+export function SetFileCacheLimits(arg1:number,arg2:number):Promise<main.FileCacheStats>;
+
+export function ValidateSAATargetPort(arg1:main.SAAValidationRequest):Promise<main.SAAValidationReport>;
+
 export function WriteJsonFile(arg1:string,arg2:string):Promise<void>;
+
+export function WriteOutputManifest(arg1:string,arg2:string,arg3:string):Promise<main.OutputManifest>;
+
+export function WriteShockDefinitions(arg1:main.ShockWriteRequest):Promise<main.ShockSet>;
@@ -2,10 +2,46 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AggregateOutput(arg1) {
+  return window['go']['main']['App']['AggregateOutput'](arg1);
+}
+
+export function ArchiveScenarioConfig(arg1, arg2) {
+  return window['go']['main']['App']['ArchiveScenarioConfig'](arg1, arg2);
+}
+
+export function BuildCurve(arg1) {
+  return window['go']['main']['App']['BuildCurve'](arg1);
+}
+
+export function CloneScenarioConfig(arg1, arg2) {
+  return window['go']['main']['App']['CloneScenarioConfig'](arg1, arg2);
+}
+
+export function CompareOutputs(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CompareOutputs'](arg1, arg2, arg3);
+}
+
 export function CopyFileToDownloads(arg1, arg2) {
   return window['go']['main']['App']['CopyFileToDownloads'](arg1, arg2);
 }
 
+export function DeleteRegressionBaseline(arg1) {
+  return window['go']['main']['App']['DeleteRegressionBaseline'](arg1);
+}
+
+export function DeleteScenarioConfig(arg1, arg2) {
+  return window['go']['main']['App']['DeleteScenarioConfig'](arg1, arg2);
+}
+
+export function DescribeCSV(arg1) {
+  return window['go']['main']['App']['DescribeCSV'](arg1);
+}
+
+export function DescribeScenarioConfig(arg1) {
+  return window['go']['main']['App']['DescribeScenarioConfig'](arg1);
+}
+
 export function ExecutePalm(arg1, arg2, arg3) {
   return window['go']['main']['App']['ExecutePalm'](arg1, arg2, arg3);
 }
@@ -14,6 +50,30 @@ export function ExecutePythonScript(arg1, arg2) {
   return window['go']['main']['App']['ExecutePythonScript'](arg1, arg2);
 }
 
+export function ExportRunToExcel(arg1) {
+  return window['go']['main']['App']['ExportRunToExcel'](arg1);
+}
+
+export function ExportRunToParquet(arg1, arg2) {
+  return window['go']['main']['App']['ExportRunToParquet'](arg1, arg2);
+}
+
+export function ExportSAATargetPort(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportSAATargetPort'](arg1, arg2, arg3);
+}
+
+export function GenerateScenarios(arg1) {
+  return window['go']['main']['App']['GenerateScenarios'](arg1);
+}
+
+export function GenerateStochasticScenarios(arg1) {
+  return window['go']['main']['App']['GenerateStochasticScenarios'](arg1);
+}
+
+export function GetFileCacheStats() {
+  return window['go']['main']['App']['GetFileCacheStats']();
+}
+
 export function GetFilenames(arg1) {
   return window['go']['main']['App']['GetFilenames'](arg1);
 }
@@ -22,6 +82,42 @@ export function GetLiabilityConfigs(arg1) {
   return window['go']['main']['App']['GetLiabilityConfigs'](arg1);
 }
 
+export function GetPythonInvocations() {
+  return window['go']['main']['App']['GetPythonInvocations']();
+}
+
+export function GetRegressionReport(arg1) {
+  return window['go']['main']['App']['GetRegressionReport'](arg1);
+}
+
+export function GetRegressionReports() {
+  return window['go']['main']['App']['GetRegressionReports']();
+}
+
+export function ImportSAATargetPort(arg1) {
+  return window['go']['main']['App']['ImportSAATargetPort'](arg1);
+}
+
+export function ImportSpotRates(arg1) {
+  return window['go']['main']['App']['ImportSpotRates'](arg1);
+}
+
+export function InvalidateFileCache(arg1) {
+  return window['go']['main']['App']['InvalidateFileCache'](arg1);
+}
+
+export function ListRegressionBaselines() {
+  return window['go']['main']['App']['ListRegressionBaselines']();
+}
+
+export function ListScenarioConfigs(arg1, arg2) {
+  return window['go']['main']['App']['ListScenarioConfigs'](arg1, arg2);
+}
+
+export function ListScenarios(arg1) {
+  return window['go']['main']['App']['ListScenarios'](arg1);
+}
+
 export function OpenFile(arg1) {
   return window['go']['main']['App']['OpenFile'](arg1);
 }
@@ -30,18 +126,106 @@ export function OpenFileDialog(arg1) {
   return window['go']['main']['App']['OpenFileDialog'](arg1);
 }
 
+export function PostProcessRun(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PostProcessRun'](arg1, arg2, arg3);
+}
+
+export function PreflightScenarioFiles(arg1, arg2) {
+  return window['go']['main']['App']['PreflightScenarioFiles'](arg1, arg2);
+}
+
+export function ReadCSVPage(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ReadCSVPage'](arg1, arg2, arg3, arg4);
+}
+
 export function ReadFiles(arg1, arg2, arg3) {
   return window['go']['main']['App']['ReadFiles'](arg1, arg2, arg3);
 }
 
+export function ReadFilesMatching(arg1, arg2) {
+  return window['go']['main']['App']['ReadFilesMatching'](arg1, arg2);
+}
+
+export function ReadOutputManifest(arg1) {
+  return window['go']['main']['App']['ReadOutputManifest'](arg1);
+}
+
+export function ReadRunFiles(arg1, arg2) {
+  return window['go']['main']['App']['ReadRunFiles'](arg1, arg2);
+}
+
+export function ReadRunTables(arg1, arg2) {
+  return window['go']['main']['App']['ReadRunTables'](arg1, arg2);
+}
+
 export function ReadScenarioConfig(arg1) {
   return window['go']['main']['App']['ReadScenarioConfig'](arg1);
 }
 
+export function ReadScenarioPaths(arg1) {
+  return window['go']['main']['App']['ReadScenarioPaths'](arg1);
+}
+
+export function ReadShockDefinitions(arg1) {
+  return window['go']['main']['App']['ReadShockDefinitions'](arg1);
+}
+
+export function ReadTable(arg1) {
+  return window['go']['main']['App']['ReadTable'](arg1);
+}
+
+export function ReadTables(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReadTables'](arg1, arg2, arg3);
+}
+
 export function ReadUIConfig() {
   return window['go']['main']['App']['ReadUIConfig']();
 }
 
+export function RegisterRegressionBaseline(arg1) {
+  return window['go']['main']['App']['RegisterRegressionBaseline'](arg1);
+}
+
+export function RenameScenarioConfig(arg1, arg2) {
+  return window['go']['main']['App']['RenameScenarioConfig'](arg1, arg2);
+}
+
+export function ResolvePalmLauncher(arg1) {
+  return window['go']['main']['App']['ResolvePalmLauncher'](arg1);
+}
+
+export function ResolvePythonInterpreter(arg1) {
+  return window['go']['main']['App']['ResolvePythonInterpreter'](arg1);
+}
+
+export function RunOutputPath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RunOutputPath'](arg1, arg2, arg3);
+}
+
+export function RunRegressionSuite(arg1) {
+  return window['go']['main']['App']['RunRegressionSuite'](arg1);
+}
+
+export function ScenarioStatistics(arg1) {
+  return window['go']['main']['App']['ScenarioStatistics'](arg1);
+}
+
+export function SetFileCacheLimits(arg1, arg2) {
+  return window['go']['main']['App']['SetFileCacheLimits'](arg1, arg2);
+}
+
+export function ValidateSAATargetPort(arg1) {
+  return window['go']['main']['App']['ValidateSAATargetPort'](arg1);
+}
+
 export function WriteJsonFile(arg1, arg2) {
   return window['go']['main']['App']['WriteJsonFile'](arg1, arg2);
 }
+
+export function WriteOutputManifest(arg1, arg2, arg3) {
+  return window['go']['main']['App']['WriteOutputManifest'](arg1, arg2, arg3);
+}
+
+export function WriteShockDefinitions(arg1) {
+  return window['go']['main']['App']['WriteShockDefinitions'](arg1);
+}
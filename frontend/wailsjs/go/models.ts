@@ -1,5 +1,117 @@
 export namespace main {
 	
+	export class AggregatedSeries {
+	    Group: string;
+	    Column: string;
+	    Periods: number[];
+	    Values: number[];
+	    PresentValue: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AggregatedSeries(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Group = source["Group"];
+	        this.Column = source["Column"];
+	        this.Periods = source["Periods"];
+	        this.Values = source["Values"];
+	        this.PresentValue = source["PresentValue"];
+	    }
+	}
+	export class AggregationRequest {
+	    Path: string;
+	    FilterString: string;
+	    WalkSubdirectories: boolean;
+	    Columns: string[];
+	    TimeColumn: string;
+	    BlockColumn: string;
+	    Blocks: number[];
+	    Frequency: string;
+	    Method: string;
+	    GroupBy: string;
+	    DiscountRate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AggregationRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.FilterString = source["FilterString"];
+	        this.WalkSubdirectories = source["WalkSubdirectories"];
+	        this.Columns = source["Columns"];
+	        this.TimeColumn = source["TimeColumn"];
+	        this.BlockColumn = source["BlockColumn"];
+	        this.Blocks = source["Blocks"];
+	        this.Frequency = source["Frequency"];
+	        this.Method = source["Method"];
+	        this.GroupBy = source["GroupBy"];
+	        this.DiscountRate = source["DiscountRate"];
+	    }
+	}
+	export class AggregationResult {
+	    Frequency: string;
+	    Method: string;
+	    GroupBy: string;
+	    Files: string[];
+	    Series: AggregatedSeries[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AggregationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Frequency = source["Frequency"];
+	        this.Method = source["Method"];
+	        this.GroupBy = source["GroupBy"];
+	        this.Files = source["Files"];
+	        this.Series = this.convertValues(source["Series"], AggregatedSeries);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CSVDescription {
+	    Path: string;
+	    Name: string;
+	    SizeBytes: number;
+	    Headers: string[];
+	    ColumnCount: number;
+	    RowCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CSVDescription(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Name = source["Name"];
+	        this.SizeBytes = source["SizeBytes"];
+	        this.Headers = source["Headers"];
+	        this.ColumnCount = source["ColumnCount"];
+	        this.RowCount = source["RowCount"];
+	    }
+	}
 	export class CSVFile {
 	    Path: string;
 	    Name: string;
@@ -16,6 +128,142 @@ export namespace main {
 	        this.Data = source["Data"];
 	    }
 	}
+	export class CSVPage {
+	    Path: string;
+	    Headers: string[];
+	    Offset: number;
+	    Rows: string[][];
+	    HasMore: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CSVPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Headers = source["Headers"];
+	        this.Offset = source["Offset"];
+	        this.Rows = source["Rows"];
+	        this.HasMore = source["HasMore"];
+	    }
+	}
+	export class CTEValue {
+	    Level: number;
+	    Value: number;
+	    TailCount: number;
+	    Scenarios: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CTEValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Level = source["Level"];
+	        this.Value = source["Value"];
+	        this.TailCount = source["TailCount"];
+	        this.Scenarios = source["Scenarios"];
+	    }
+	}
+	export class CellDiff {
+	    File: string;
+	    Key: string;
+	    Column: string;
+	    A?: number;
+	    B?: number;
+	    TextA: string;
+	    TextB: string;
+	    AbsDiff?: number;
+	    RelDiff?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CellDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.File = source["File"];
+	        this.Key = source["Key"];
+	        this.Column = source["Column"];
+	        this.A = source["A"];
+	        this.B = source["B"];
+	        this.TextA = source["TextA"];
+	        this.TextB = source["TextB"];
+	        this.AbsDiff = source["AbsDiff"];
+	        this.RelDiff = source["RelDiff"];
+	    }
+	}
+	export class ReadFilesOptions {
+	    Filter: string;
+	    Include: string[];
+	    Exclude: string[];
+	    UseRegex: boolean;
+	    MaxDepth: number;
+	    SortBy: string;
+	    Desc: boolean;
+	    MaxFiles: number;
+	    Formats: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReadFilesOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Filter = source["Filter"];
+	        this.Include = source["Include"];
+	        this.Exclude = source["Exclude"];
+	        this.UseRegex = source["UseRegex"];
+	        this.MaxDepth = source["MaxDepth"];
+	        this.SortBy = source["SortBy"];
+	        this.Desc = source["Desc"];
+	        this.MaxFiles = source["MaxFiles"];
+	        this.Formats = source["Formats"];
+	    }
+	}
+	export class CompareOptions {
+	    Files: ReadFilesOptions;
+	    RunNameA: string;
+	    RunNameB: string;
+	    KeyColumns: string[];
+	    AbsTolerance: number;
+	    RelTolerance: number;
+	    MaxRows: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Files = this.convertValues(source["Files"], ReadFilesOptions);
+	        this.RunNameA = source["RunNameA"];
+	        this.RunNameB = source["RunNameB"];
+	        this.KeyColumns = source["KeyColumns"];
+	        this.AbsTolerance = source["AbsTolerance"];
+	        this.RelTolerance = source["RelTolerance"];
+	        this.MaxRows = source["MaxRows"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Config {
 	    uiDirectory: string;
 	    palmFolderPath: string;
@@ -32,9 +280,16 @@ export namespace main {
 	    pythonParserScript: string;
 	    pythonLiabilityConfigScript: string;
 	    pythonSpreadAssumptionScript: string;
+	    usePythonParser: boolean;
 	    baseScenarioConfigPath: string;
 	    scenarioConfigsPath: string;
 	    pythonGenerateScenarioScript: string;
+	    usePythonGenerator: boolean;
+	    pythonInterpreterPath: string;
+	    pythonVenvPath: string;
+	    pythonCondaEnv: string;
+	    pythonScriptInterpreters: {[key: string]: string};
+	    regressionSuitePath: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Config(source);
@@ -57,9 +312,174 @@ export namespace main {
 	        this.pythonParserScript = source["pythonParserScript"];
 	        this.pythonLiabilityConfigScript = source["pythonLiabilityConfigScript"];
 	        this.pythonSpreadAssumptionScript = source["pythonSpreadAssumptionScript"];
+	        this.usePythonParser = source["usePythonParser"];
 	        this.baseScenarioConfigPath = source["baseScenarioConfigPath"];
 	        this.scenarioConfigsPath = source["scenarioConfigsPath"];
 	        this.pythonGenerateScenarioScript = source["pythonGenerateScenarioScript"];
+	        this.usePythonGenerator = source["usePythonGenerator"];
+	        this.pythonInterpreterPath = source["pythonInterpreterPath"];
+	        this.pythonVenvPath = source["pythonVenvPath"];
+	        this.pythonCondaEnv = source["pythonCondaEnv"];
+	        this.pythonScriptInterpreters = source["pythonScriptInterpreters"];
+	        this.regressionSuitePath = source["regressionSuitePath"];
+	    }
+	}
+	export class CurveNode {
+	    Tenor: string;
+	    Years: number;
+	    Rate: number;
+	    Zero: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurveNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Tenor = source["Tenor"];
+	        this.Years = source["Years"];
+	        this.Rate = source["Rate"];
+	        this.Zero = source["Zero"];
+	    }
+	}
+	export class CurvePoint {
+	    Month: number;
+	    Years: number;
+	    Zero: number;
+	    Forward: number;
+	    Discount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurvePoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Month = source["Month"];
+	        this.Years = source["Years"];
+	        this.Zero = source["Zero"];
+	        this.Forward = source["Forward"];
+	        this.Discount = source["Discount"];
+	    }
+	}
+	export class ExcelSheet {
+	    Path: string;
+	    Name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExcelSheet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Name = source["Name"];
+	    }
+	}
+	export class ExcelExportRequest {
+	    RunName: string;
+	    Sheets: ExcelSheet[];
+	    LiabilityConfigPath: string;
+	    DestPath: string;
+	    FileName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExcelExportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.RunName = source["RunName"];
+	        this.Sheets = this.convertValues(source["Sheets"], ExcelSheet);
+	        this.LiabilityConfigPath = source["LiabilityConfigPath"];
+	        this.DestPath = source["DestPath"];
+	        this.FileName = source["FileName"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FanBand {
+	    Percentile: number;
+	    Values: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FanBand(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Percentile = source["Percentile"];
+	        this.Values = source["Values"];
+	    }
+	}
+	export class FileCacheStats {
+	    Entries: number;
+	    SizeBytes: number;
+	    MaxEntries: number;
+	    MaxBytes: number;
+	    Hits: number;
+	    Misses: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileCacheStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Entries = source["Entries"];
+	        this.SizeBytes = source["SizeBytes"];
+	        this.MaxEntries = source["MaxEntries"];
+	        this.MaxBytes = source["MaxBytes"];
+	        this.Hits = source["Hits"];
+	        this.Misses = source["Misses"];
+	    }
+	}
+	export class FileComparison {
+	    Pattern: string;
+	    PathA: string;
+	    PathB: string;
+	    KeyColumns: string[];
+	    RowsA: number;
+	    RowsB: number;
+	    RowsOnlyA: number;
+	    RowsOnlyB: number;
+	    ColumnsOnlyA: string[];
+	    ColumnsOnlyB: string[];
+	    ChangedCells: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Pattern = source["Pattern"];
+	        this.PathA = source["PathA"];
+	        this.PathB = source["PathB"];
+	        this.KeyColumns = source["KeyColumns"];
+	        this.RowsA = source["RowsA"];
+	        this.RowsB = source["RowsB"];
+	        this.RowsOnlyA = source["RowsOnlyA"];
+	        this.RowsOnlyB = source["RowsOnlyB"];
+	        this.ColumnsOnlyA = source["ColumnsOnlyA"];
+	        this.ColumnsOnlyB = source["ColumnsOnlyB"];
+	        this.ChangedCells = source["ChangedCells"];
 	    }
 	}
 	export class FileDialogOptions {
@@ -76,6 +496,44 @@ export namespace main {
 	        this.DefaultDirectory = source["DefaultDirectory"];
 	    }
 	}
+	export class GeneratedScenarioFile {
+	    Path: string;
+	    Curve: string;
+	    Loop: string;
+	    Scenarios: number;
+	    Rows: number;
+	    SHA256: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GeneratedScenarioFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Curve = source["Curve"];
+	        this.Loop = source["Loop"];
+	        this.Scenarios = source["Scenarios"];
+	        this.Rows = source["Rows"];
+	        this.SHA256 = source["SHA256"];
+	    }
+	}
+	export class HistogramBin {
+	    Lower: number;
+	    Upper: number;
+	    Count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistogramBin(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Lower = source["Lower"];
+	        this.Upper = source["Upper"];
+	        this.Count = source["Count"];
+	    }
+	}
 	export class SAATargetPort {
 	    public_agg: number;
 	    public_big: number;
@@ -990,52 +1448,975 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class ManifestFile {
+	    Path: string;
+	    Name: string;
+	    SizeBytes: number;
+	    SHA256: string;
+	    ModifiedAt: string;
+	    Tabular: boolean;
+	    Rows: number;
+	    Columns: number;
+	    Schema: string;
+	    Error: string;
 	
-	export class ScenarioConfig {
-	    Asof: string;
-	    run_id: string;
-	    output_path: string;
-	    NumberOfYears: number;
-	    NumberOfYearsInner: number;
-	    monthPerYear: number;
-	    Extrapolation: string;
-	    UFROuter: number;
-	    UFROuterStartMonth: number;
-	    UFRInner: number;
-	    UFRInnerStartMonth: number;
-	    TenorsOfInterests: string[];
-	    BaseShockScenarios: string[];
-	    InnerShockScenarios: string[];
-	    DictShockedMonth: {[key: string]: number};
-	    RateConvention: string;
-	    listOfInnerProjectionMonth: any;
-	    NumberOfYearSuperSet: number;
-	    ShockInputFile: string;
-	    VolInputFile: string;
-	    SwapInputFile: string;
-	    VolForOuter: number;
-	    VolForInner: number;
-	    SwapForOuter: number;
-	    SwapForInner: number;
-	    ifFlat: number;
-	    ifReset: number;
-	    ResetMonth: number;
-	    sofrSpotRate: {[key: string]: number};
-	    trSpotRate: {[key: string]: number};
+	    static createFrom(source: any = {}) {
+	        return new ManifestFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Name = source["Name"];
+	        this.SizeBytes = source["SizeBytes"];
+	        this.SHA256 = source["SHA256"];
+	        this.ModifiedAt = source["ModifiedAt"];
+	        this.Tabular = source["Tabular"];
+	        this.Rows = source["Rows"];
+	        this.Columns = source["Columns"];
+	        this.Schema = source["Schema"];
+	        this.Error = source["Error"];
+	    }
+	}
+	export class MetricDiff {
+	    File: string;
+	    Column: string;
+	    CellsCompared: number;
+	    CellsChanged: number;
+	    SumA: number;
+	    SumB: number;
+	    MaxAbsDiff: number;
+	    MaxRelDiff?: number;
+	    MaxDiffKey: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ScenarioConfig(source);
+	        return new MetricDiff(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.Asof = source["Asof"];
+	        this.File = source["File"];
+	        this.Column = source["Column"];
+	        this.CellsCompared = source["CellsCompared"];
+	        this.CellsChanged = source["CellsChanged"];
+	        this.SumA = source["SumA"];
+	        this.SumB = source["SumB"];
+	        this.MaxAbsDiff = source["MaxAbsDiff"];
+	        this.MaxRelDiff = source["MaxRelDiff"];
+	        this.MaxDiffKey = source["MaxDiffKey"];
+	    }
+	}
+	export class OutputComparison {
+	    RunA: string;
+	    RunB: string;
+	    Files: FileComparison[];
+	    OnlyInA: string[];
+	    OnlyInB: string[];
+	    Metrics: MetricDiff[];
+	    ChangedMetrics: number;
+	    Rows: CellDiff[];
+	    Truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new OutputComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.RunA = source["RunA"];
+	        this.RunB = source["RunB"];
+	        this.Files = this.convertValues(source["Files"], FileComparison);
+	        this.OnlyInA = source["OnlyInA"];
+	        this.OnlyInB = source["OnlyInB"];
+	        this.Metrics = this.convertValues(source["Metrics"], MetricDiff);
+	        this.ChangedMetrics = source["ChangedMetrics"];
+	        this.Rows = this.convertValues(source["Rows"], CellDiff);
+	        this.Truncated = source["Truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OutputManifest {
+	    RunName: string;
+	    OutputFolder: string;
+	    StartedAt: string;
+	    CreatedAt: string;
+	    FileCount: number;
+	    TotalBytes: number;
+	    Files: ManifestFile[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OutputManifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.RunName = source["RunName"];
+	        this.OutputFolder = source["OutputFolder"];
+	        this.StartedAt = source["StartedAt"];
+	        this.CreatedAt = source["CreatedAt"];
+	        this.FileCount = source["FileCount"];
+	        this.TotalBytes = source["TotalBytes"];
+	        this.Files = this.convertValues(source["Files"], ManifestFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TableColumn {
+	    Name: string;
+	    Unit: string;
+	    Type: string;
+	    Values: number[];
+	    Strings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TableColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Unit = source["Unit"];
+	        this.Type = source["Type"];
+	        this.Values = source["Values"];
+	        this.Strings = source["Strings"];
+	    }
+	}
+	export class OutputTable {
+	    Path: string;
+	    Name: string;
+	    Schema: string;
+	    KeyColumns: string[];
+	    Headers: string[];
+	    RowCount: number;
+	    Columns: TableColumn[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OutputTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Name = source["Name"];
+	        this.Schema = source["Schema"];
+	        this.KeyColumns = source["KeyColumns"];
+	        this.Headers = source["Headers"];
+	        this.RowCount = source["RowCount"];
+	        this.Columns = this.convertValues(source["Columns"], TableColumn);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PalmLauncher {
+	    Path: string;
+	    Args: string[];
+	    Dir: string;
+	    Kind: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PalmLauncher(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Args = source["Args"];
+	        this.Dir = source["Dir"];
+	        this.Kind = source["Kind"];
+	    }
+	}
+	export class ParquetExportFile {
+	    Source: string;
+	    Path: string;
+	    Rows: number;
+	    Columns: number;
+	    SizeBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ParquetExportFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Source = source["Source"];
+	        this.Path = source["Path"];
+	        this.Rows = source["Rows"];
+	        this.Columns = source["Columns"];
+	        this.SizeBytes = source["SizeBytes"];
+	    }
+	}
+	export class ParquetExport {
+	    SourceFolder: string;
+	    DestFolder: string;
+	    Files: ParquetExportFile[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ParquetExport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.SourceFolder = source["SourceFolder"];
+	        this.DestFolder = source["DestFolder"];
+	        this.Files = this.convertValues(source["Files"], ParquetExportFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PercentileValue {
+	    Percentile: number;
+	    Value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PercentileValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Percentile = source["Percentile"];
+	        this.Value = source["Value"];
+	    }
+	}
+	export class PostProcessResult {
+	    Module: string;
+	    OutputFolder: string;
+	    Engine: string;
+	    Files: string[];
+	    Output: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PostProcessResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Module = source["Module"];
+	        this.OutputFolder = source["OutputFolder"];
+	        this.Engine = source["Engine"];
+	        this.Files = source["Files"];
+	        this.Output = source["Output"];
+	    }
+	}
+	export class PreflightFile {
+	    Field: string;
+	    Path: string;
+	    ResolvedPath: string;
+	    Loop: string;
+	    Scenarios: number;
+	    Months: number;
+	    Tenors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PreflightFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Field = source["Field"];
+	        this.Path = source["Path"];
+	        this.ResolvedPath = source["ResolvedPath"];
+	        this.Loop = source["Loop"];
+	        this.Scenarios = source["Scenarios"];
+	        this.Months = source["Months"];
+	        this.Tenors = source["Tenors"];
+	    }
+	}
+	export class PreflightIssue {
+	    Severity: string;
+	    Field: string;
+	    Path: string;
+	    Message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PreflightIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Severity = source["Severity"];
+	        this.Field = source["Field"];
+	        this.Path = source["Path"];
+	        this.Message = source["Message"];
+	    }
+	}
+	export class PreflightReport {
+	    Passed: boolean;
+	    RequiredScenarios: number;
+	    RequiredInnerScenarios: number;
+	    RequiredMonths: number;
+	    Files: PreflightFile[];
+	    Issues: PreflightIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PreflightReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Passed = source["Passed"];
+	        this.RequiredScenarios = source["RequiredScenarios"];
+	        this.RequiredInnerScenarios = source["RequiredInnerScenarios"];
+	        this.RequiredMonths = source["RequiredMonths"];
+	        this.Files = this.convertValues(source["Files"], PreflightFile);
+	        this.Issues = this.convertValues(source["Issues"], PreflightIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PythonInterpreter {
+	    Path: string;
+	    Args: string[];
+	    Version: string;
+	    Source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PythonInterpreter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Args = source["Args"];
+	        this.Version = source["Version"];
+	        this.Source = source["Source"];
+	    }
+	}
+	export class PythonInvocation {
+	    ScriptPath: string;
+	    Params: string[];
+	    Interpreter: PythonInterpreter;
+	    StartedAt: string;
+	    DurationMs: number;
+	    Error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PythonInvocation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ScriptPath = source["ScriptPath"];
+	        this.Params = source["Params"];
+	        this.Interpreter = this.convertValues(source["Interpreter"], PythonInterpreter);
+	        this.StartedAt = source["StartedAt"];
+	        this.DurationMs = source["DurationMs"];
+	        this.Error = source["Error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RegisterBaselineRequest {
+	    Name: string;
+	    Description: string;
+	    ConfigPath: string;
+	    OutputFolder: string;
+	    Module: string;
+	    EnginePath: string;
+	    Files: ReadFilesOptions;
+	    AbsTolerance: number;
+	    RelTolerance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RegisterBaselineRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Description = source["Description"];
+	        this.ConfigPath = source["ConfigPath"];
+	        this.OutputFolder = source["OutputFolder"];
+	        this.Module = source["Module"];
+	        this.EnginePath = source["EnginePath"];
+	        this.Files = this.convertValues(source["Files"], ReadFilesOptions);
+	        this.AbsTolerance = source["AbsTolerance"];
+	        this.RelTolerance = source["RelTolerance"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RegressionBaseline {
+	    Name: string;
+	    Description: string;
+	    CreatedAt: string;
+	    RunName: string;
+	    Module: string;
+	    SourceConfigPath: string;
+	    SourceOutputPath: string;
+	    EnginePath: string;
+	    Files: ReadFilesOptions;
+	    OutputFiles: string[];
+	    AbsTolerance: number;
+	    RelTolerance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RegressionBaseline(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Description = source["Description"];
+	        this.CreatedAt = source["CreatedAt"];
+	        this.RunName = source["RunName"];
+	        this.Module = source["Module"];
+	        this.SourceConfigPath = source["SourceConfigPath"];
+	        this.SourceOutputPath = source["SourceOutputPath"];
+	        this.EnginePath = source["EnginePath"];
+	        this.Files = this.convertValues(source["Files"], ReadFilesOptions);
+	        this.OutputFiles = source["OutputFiles"];
+	        this.AbsTolerance = source["AbsTolerance"];
+	        this.RelTolerance = source["RelTolerance"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RegressionResult {
+	    Baseline: string;
+	    Passed: boolean;
+	    Failures: string[];
+	    Error: string;
+	    OutputFolder: string;
+	    LogPath: string;
+	    DurationMs: number;
+	    Comparison?: OutputComparison;
+	
+	    static createFrom(source: any = {}) {
+	        return new RegressionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Baseline = source["Baseline"];
+	        this.Passed = source["Passed"];
+	        this.Failures = source["Failures"];
+	        this.Error = source["Error"];
+	        this.OutputFolder = source["OutputFolder"];
+	        this.LogPath = source["LogPath"];
+	        this.DurationMs = source["DurationMs"];
+	        this.Comparison = this.convertValues(source["Comparison"], OutputComparison);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RegressionReport {
+	    ID: string;
+	    StartedAt: string;
+	    DurationMs: number;
+	    EnginePath: string;
+	    Passed: boolean;
+	    PassedCount: number;
+	    FailedCount: number;
+	    Results: RegressionResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RegressionReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ID = source["ID"];
+	        this.StartedAt = source["StartedAt"];
+	        this.DurationMs = source["DurationMs"];
+	        this.EnginePath = source["EnginePath"];
+	        this.Passed = source["Passed"];
+	        this.PassedCount = source["PassedCount"];
+	        this.FailedCount = source["FailedCount"];
+	        this.Results = this.convertValues(source["Results"], RegressionResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RegressionRunRequest {
+	    Baselines: string[];
+	    EnginePath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RegressionRunRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Baselines = source["Baselines"];
+	        this.EnginePath = source["EnginePath"];
+	    }
+	}
+	export class SAABound {
+	    AssetClass: string;
+	    Min: number;
+	    Max?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SAABound(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.AssetClass = source["AssetClass"];
+	        this.Min = source["Min"];
+	        this.Max = source["Max"];
+	    }
+	}
+	export class SAAImportFile {
+	    Path: string;
+	    Portfolio: string;
+	    Periods: number;
+	    Unit: string;
+	    UnknownColumns: string[];
+	    MissingColumns: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAImportFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Portfolio = source["Portfolio"];
+	        this.Periods = source["Periods"];
+	        this.Unit = source["Unit"];
+	        this.UnknownColumns = source["UnknownColumns"];
+	        this.MissingColumns = source["MissingColumns"];
+	    }
+	}
+	export class SAAImportRequest {
+	    Config: LiabilityConfig;
+	    ConfigFolder: string;
+	    OuterPath: string;
+	    InnerPath: string;
+	    Bounds: SAABound[];
+	    Normalise: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAImportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Config = this.convertValues(source["Config"], LiabilityConfig);
+	        this.ConfigFolder = source["ConfigFolder"];
+	        this.OuterPath = source["OuterPath"];
+	        this.InnerPath = source["InnerPath"];
+	        this.Bounds = this.convertValues(source["Bounds"], SAABound);
+	        this.Normalise = source["Normalise"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SAAPeriodCheck {
+	    Period: number;
+	    Sum: number;
+	    Valid: boolean;
+	    Normalised: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAPeriodCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Period = source["Period"];
+	        this.Sum = source["Sum"];
+	        this.Valid = source["Valid"];
+	        this.Normalised = source["Normalised"];
+	    }
+	}
+	export class SAAPortfolioCheck {
+	    Portfolio: string;
+	    Field: string;
+	    Unit: string;
+	    Periods: SAAPeriodCheck[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAPortfolioCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Portfolio = source["Portfolio"];
+	        this.Field = source["Field"];
+	        this.Unit = source["Unit"];
+	        this.Periods = this.convertValues(source["Periods"], SAAPeriodCheck);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SAAIssue {
+	    Severity: string;
+	    Portfolio: string;
+	    Period: number;
+	    AssetClass: string;
+	    Message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Severity = source["Severity"];
+	        this.Portfolio = source["Portfolio"];
+	        this.Period = source["Period"];
+	        this.AssetClass = source["AssetClass"];
+	        this.Message = source["Message"];
+	    }
+	}
+	export class SAAValidationReport {
+	    Passed: boolean;
+	    AssetClasses: string[];
+	    ExpectedPeriods: number;
+	    Portfolios: SAAPortfolioCheck[];
+	    Issues: SAAIssue[];
+	    SAATargetPort: SAATargetPort[];
+	    SAATargetPortInner: SAATargetPort[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAValidationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Passed = source["Passed"];
+	        this.AssetClasses = source["AssetClasses"];
+	        this.ExpectedPeriods = source["ExpectedPeriods"];
+	        this.Portfolios = this.convertValues(source["Portfolios"], SAAPortfolioCheck);
+	        this.Issues = this.convertValues(source["Issues"], SAAIssue);
+	        this.SAATargetPort = this.convertValues(source["SAATargetPort"], SAATargetPort);
+	        this.SAATargetPortInner = this.convertValues(source["SAATargetPortInner"], SAATargetPort);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SAAImportResult {
+	    Passed: boolean;
+	    ConfigPath: string;
+	    Config: LiabilityConfig;
+	    Files: SAAImportFile[];
+	    Report?: SAAValidationReport;
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Passed = source["Passed"];
+	        this.ConfigPath = source["ConfigPath"];
+	        this.Config = this.convertValues(source["Config"], LiabilityConfig);
+	        this.Files = this.convertValues(source["Files"], SAAImportFile);
+	        this.Report = this.convertValues(source["Report"], SAAValidationReport);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SAAValidationRequest {
+	    Config: LiabilityConfig;
+	    Bounds: SAABound[];
+	    Tolerance: number;
+	    Normalise: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SAAValidationRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Config = this.convertValues(source["Config"], LiabilityConfig);
+	        this.Bounds = this.convertValues(source["Bounds"], SAABound);
+	        this.Tolerance = source["Tolerance"];
+	        this.Normalise = source["Normalise"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioConfig {
+	    Asof: string;
+	    run_id: string;
+	    output_path: string;
+	    NumberOfYears: number;
+	    NumberOfYearsInner: number;
+	    monthPerYear: number;
+	    Extrapolation: string;
+	    Interpolation?: string;
+	    UFROuter: number;
+	    UFROuterStartMonth: number;
+	    UFRInner: number;
+	    UFRInnerStartMonth: number;
+	    TenorsOfInterests: string[];
+	    BaseShockScenarios: string[];
+	    InnerShockScenarios: string[];
+	    DictShockedMonth: {[key: string]: number};
+	    RateConvention: string;
+	    listOfInnerProjectionMonth: any;
+	    NumberOfYearSuperSet: number;
+	    ShockInputFile: string;
+	    VolInputFile: string;
+	    SwapInputFile: string;
+	    VolForOuter: number;
+	    VolForInner: number;
+	    SwapForOuter: number;
+	    SwapForInner: number;
+	    ifFlat: number;
+	    ifReset: number;
+	    ResetMonth: number;
+	    sofrSpotRate: {[key: string]: number};
+	    trSpotRate: {[key: string]: number};
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Asof = source["Asof"];
 	        this.run_id = source["run_id"];
 	        this.output_path = source["output_path"];
 	        this.NumberOfYears = source["NumberOfYears"];
 	        this.NumberOfYearsInner = source["NumberOfYearsInner"];
 	        this.monthPerYear = source["monthPerYear"];
 	        this.Extrapolation = source["Extrapolation"];
+	        this.Interpolation = source["Interpolation"];
 	        this.UFROuter = source["UFROuter"];
 	        this.UFROuterStartMonth = source["UFROuterStartMonth"];
 	        this.UFRInner = source["UFRInner"];
@@ -1061,6 +2442,678 @@ export namespace main {
 	        this.trSpotRate = source["trSpotRate"];
 	    }
 	}
+	export class StochasticScenarioRequest {
+	    ConfigPath: string;
+	    MeanReversion: number;
+	    Volatility: number;
+	    Scenarios: number;
+	    InnerScenarios: number;
+	    Seed: number;
+	    Antithetic: boolean;
+	    OuterTemplatePath: string;
+	    InnerTemplatePath: string;
+	    LiabilityConfigPath: string;
+	    PalmFolderPath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StochasticScenarioRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ConfigPath = source["ConfigPath"];
+	        this.MeanReversion = source["MeanReversion"];
+	        this.Volatility = source["Volatility"];
+	        this.Scenarios = source["Scenarios"];
+	        this.InnerScenarios = source["InnerScenarios"];
+	        this.Seed = source["Seed"];
+	        this.Antithetic = source["Antithetic"];
+	        this.OuterTemplatePath = source["OuterTemplatePath"];
+	        this.InnerTemplatePath = source["InnerTemplatePath"];
+	        this.LiabilityConfigPath = source["LiabilityConfigPath"];
+	        this.PalmFolderPath = source["PalmFolderPath"];
+	    }
+	}
+	export class ScenarioGenerationResult {
+	    RunID: string;
+	    ConfigPath: string;
+	    ConfigSHA256: string;
+	    OutputFolder: string;
+	    Engine: string;
+	    CreatedAt: string;
+	    DurationMs: number;
+	    Files: GeneratedScenarioFile[];
+	    Hash: string;
+	    Output: string;
+	    Stochastic?: StochasticScenarioRequest;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioGenerationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.RunID = source["RunID"];
+	        this.ConfigPath = source["ConfigPath"];
+	        this.ConfigSHA256 = source["ConfigSHA256"];
+	        this.OutputFolder = source["OutputFolder"];
+	        this.Engine = source["Engine"];
+	        this.CreatedAt = source["CreatedAt"];
+	        this.DurationMs = source["DurationMs"];
+	        this.Files = this.convertValues(source["Files"], GeneratedScenarioFile);
+	        this.Hash = source["Hash"];
+	        this.Output = source["Output"];
+	        this.Stochastic = this.convertValues(source["Stochastic"], StochasticScenarioRequest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioConfigDetails {
+	    Config?: ScenarioConfig;
+	    Generation?: ScenarioGenerationResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioConfigDetails(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Config = this.convertValues(source["Config"], ScenarioConfig);
+	        this.Generation = this.convertValues(source["Generation"], ScenarioGenerationResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioConfigEntry {
+	    Name: string;
+	    Path: string;
+	    RunID: string;
+	    Asof: string;
+	    OutputFolder: string;
+	    ScenarioFiles: string[];
+	    FilesExist: boolean;
+	    MissingFiles: string[];
+	    ReferencedBy: string[];
+	    Archived: boolean;
+	    Base: boolean;
+	    ModifiedAt: string;
+	    Error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioConfigEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Path = source["Path"];
+	        this.RunID = source["RunID"];
+	        this.Asof = source["Asof"];
+	        this.OutputFolder = source["OutputFolder"];
+	        this.ScenarioFiles = source["ScenarioFiles"];
+	        this.FilesExist = source["FilesExist"];
+	        this.MissingFiles = source["MissingFiles"];
+	        this.ReferencedBy = source["ReferencedBy"];
+	        this.Archived = source["Archived"];
+	        this.Base = source["Base"];
+	        this.ModifiedAt = source["ModifiedAt"];
+	        this.Error = source["Error"];
+	    }
+	}
+	export class ScenarioCurve {
+	    Name: string;
+	    Loop: string;
+	    UFR: number;
+	    Alpha: number;
+	    Nodes: CurveNode[];
+	    Points: CurvePoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioCurve(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Loop = source["Loop"];
+	        this.UFR = source["UFR"];
+	        this.Alpha = source["Alpha"];
+	        this.Nodes = this.convertValues(source["Nodes"], CurveNode);
+	        this.Points = this.convertValues(source["Points"], CurvePoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioCurves {
+	    Asof: string;
+	    Convention: string;
+	    Interpolation: string;
+	    Extrapolation: string;
+	    Unit: string;
+	    MonthPerYear: number;
+	    Months: number;
+	    Curves: ScenarioCurve[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioCurves(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Asof = source["Asof"];
+	        this.Convention = source["Convention"];
+	        this.Interpolation = source["Interpolation"];
+	        this.Extrapolation = source["Extrapolation"];
+	        this.Unit = source["Unit"];
+	        this.MonthPerYear = source["MonthPerYear"];
+	        this.Months = source["Months"];
+	        this.Curves = this.convertValues(source["Curves"], ScenarioCurve);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioValue {
+	    Scenario: string;
+	    Value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Scenario = source["Scenario"];
+	        this.Value = source["Value"];
+	    }
+	}
+	export class ScenarioDistribution {
+	    Column: string;
+	    Reduce: string;
+	    Count: number;
+	    Mean: number;
+	    StdDev: number;
+	    Min: number;
+	    Max: number;
+	    Percentiles: PercentileValue[];
+	    CTE: CTEValue[];
+	    Histogram: HistogramBin[];
+	    Values: ScenarioValue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioDistribution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Column = source["Column"];
+	        this.Reduce = source["Reduce"];
+	        this.Count = source["Count"];
+	        this.Mean = source["Mean"];
+	        this.StdDev = source["StdDev"];
+	        this.Min = source["Min"];
+	        this.Max = source["Max"];
+	        this.Percentiles = this.convertValues(source["Percentiles"], PercentileValue);
+	        this.CTE = this.convertValues(source["CTE"], CTEValue);
+	        this.Histogram = this.convertValues(source["Histogram"], HistogramBin);
+	        this.Values = this.convertValues(source["Values"], ScenarioValue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioInfo {
+	    Scenario: string;
+	    Shock: string;
+	    Months: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Scenario = source["Scenario"];
+	        this.Shock = source["Shock"];
+	        this.Months = source["Months"];
+	    }
+	}
+	export class ScenarioFileSummary {
+	    Path: string;
+	    ScenarioColumn: string;
+	    MonthColumn: string;
+	    ProjectionColumn: string;
+	    Tenors: string[];
+	    Scenarios: ScenarioInfo[];
+	    ProjectionMonths: number[];
+	    Months: number;
+	    MinMonths: number;
+	    Rows: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioFileSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.ScenarioColumn = source["ScenarioColumn"];
+	        this.MonthColumn = source["MonthColumn"];
+	        this.ProjectionColumn = source["ProjectionColumn"];
+	        this.Tenors = source["Tenors"];
+	        this.Scenarios = this.convertValues(source["Scenarios"], ScenarioInfo);
+	        this.ProjectionMonths = source["ProjectionMonths"];
+	        this.Months = source["Months"];
+	        this.MinMonths = source["MinMonths"];
+	        this.Rows = source["Rows"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioPathRequest {
+	    Path: string;
+	    Scenario: string;
+	    Tenors: string[];
+	    ProjectionMonth: number;
+	    Percentiles: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioPathRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Scenario = source["Scenario"];
+	        this.Tenors = source["Tenors"];
+	        this.ProjectionMonth = source["ProjectionMonth"];
+	        this.Percentiles = source["Percentiles"];
+	    }
+	}
+	export class TenorPath {
+	    Tenor: string;
+	    Values: number[];
+	    Fan: FanBand[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TenorPath(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Tenor = source["Tenor"];
+	        this.Values = source["Values"];
+	        this.Fan = this.convertValues(source["Fan"], FanBand);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioPaths {
+	    Path: string;
+	    Scenario: string;
+	    Shock: string;
+	    ProjectionMonth: number;
+	    ScenarioCount: number;
+	    Months: number[];
+	    Tenors: TenorPath[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioPaths(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Scenario = source["Scenario"];
+	        this.Shock = source["Shock"];
+	        this.ProjectionMonth = source["ProjectionMonth"];
+	        this.ScenarioCount = source["ScenarioCount"];
+	        this.Months = source["Months"];
+	        this.Tenors = this.convertValues(source["Tenors"], TenorPath);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScenarioStatsRequest {
+	    Path: string;
+	    FilterString: string;
+	    WalkSubdirectories: boolean;
+	    Column: string;
+	    TimeColumn: string;
+	    Reduce: string;
+	    Month: number;
+	    DiscountRate: number;
+	    Percentiles: number[];
+	    CTELevels: number[];
+	    LowerTail: boolean;
+	    Bins: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScenarioStatsRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.FilterString = source["FilterString"];
+	        this.WalkSubdirectories = source["WalkSubdirectories"];
+	        this.Column = source["Column"];
+	        this.TimeColumn = source["TimeColumn"];
+	        this.Reduce = source["Reduce"];
+	        this.Month = source["Month"];
+	        this.DiscountRate = source["DiscountRate"];
+	        this.Percentiles = source["Percentiles"];
+	        this.CTELevels = source["CTELevels"];
+	        this.LowerTail = source["LowerTail"];
+	        this.Bins = source["Bins"];
+	    }
+	}
+	export class ShockDefinition {
+	    Name: string;
+	    Type: string;
+	    Bps: number;
+	    ShortBps: number;
+	    LongBps: number;
+	    BellyBps: number;
+	    PivotTenor: string;
+	    KeyTenor: string;
+	    Points: {[key: string]: number};
+	    RampMonths: number;
+	    Outer: boolean;
+	    Inner: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShockDefinition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Type = source["Type"];
+	        this.Bps = source["Bps"];
+	        this.ShortBps = source["ShortBps"];
+	        this.LongBps = source["LongBps"];
+	        this.BellyBps = source["BellyBps"];
+	        this.PivotTenor = source["PivotTenor"];
+	        this.KeyTenor = source["KeyTenor"];
+	        this.Points = source["Points"];
+	        this.RampMonths = source["RampMonths"];
+	        this.Outer = source["Outer"];
+	        this.Inner = source["Inner"];
+	    }
+	}
+	export class ShockEntry {
+	    Name: string;
+	    Bps: number[];
+	    RampMonths: number;
+	    Outer: boolean;
+	    Inner: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShockEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Bps = source["Bps"];
+	        this.RampMonths = source["RampMonths"];
+	        this.Outer = source["Outer"];
+	        this.Inner = source["Inner"];
+	    }
+	}
+	export class ShockSet {
+	    ConfigPath: string;
+	    ShockFile: string;
+	    Tenors: string[];
+	    Shocks: ShockEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ShockSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ConfigPath = source["ConfigPath"];
+	        this.ShockFile = source["ShockFile"];
+	        this.Tenors = source["Tenors"];
+	        this.Shocks = this.convertValues(source["Shocks"], ShockEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ShockWriteRequest {
+	    ConfigPath: string;
+	    ShockFile: string;
+	    Tenors: string[];
+	    Shocks: ShockDefinition[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ShockWriteRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ConfigPath = source["ConfigPath"];
+	        this.ShockFile = source["ShockFile"];
+	        this.Tenors = source["Tenors"];
+	        this.Shocks = this.convertValues(source["Shocks"], ShockDefinition);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SpotRateImport {
+	    Paths: string[];
+	    Format: string;
+	    Curve: string;
+	    Asof: string;
+	    CurveDate: string;
+	    Rates: {[key: string]: number};
+	    Unit: string;
+	    InputUnit: string;
+	    Missing: string[];
+	    Ignored: string[];
+	    Warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SpotRateImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Paths = source["Paths"];
+	        this.Format = source["Format"];
+	        this.Curve = source["Curve"];
+	        this.Asof = source["Asof"];
+	        this.CurveDate = source["CurveDate"];
+	        this.Rates = source["Rates"];
+	        this.Unit = source["Unit"];
+	        this.InputUnit = source["InputUnit"];
+	        this.Missing = source["Missing"];
+	        this.Ignored = source["Ignored"];
+	        this.Warnings = source["Warnings"];
+	    }
+	}
+	export class SpotRateImportRequest {
+	    Paths: string[];
+	    Asof: string;
+	    Curve: string;
+	    Tenors: string[];
+	    Unit: string;
+	    InputUnit: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SpotRateImportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Paths = source["Paths"];
+	        this.Asof = source["Asof"];
+	        this.Curve = source["Curve"];
+	        this.Tenors = source["Tenors"];
+	        this.Unit = source["Unit"];
+	        this.InputUnit = source["InputUnit"];
+	    }
+	}
 
 }
 
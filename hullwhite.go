@@ -0,0 +1,456 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"prismic-ui/curve"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// shock name written in the Shock column of stochastic scenario files
+const stochasticShockName = "HW1F"
+
+// StochasticScenarioRequest sets the Hull-White one-factor model a scenario config's
+// stochastic sets are generated with
+type StochasticScenarioRequest struct {
+	ConfigPath     string
+	MeanReversion  float64 // a, per year, e.g. the liability config's dblmeanreversion
+	Volatility     float64 // sigma of the short rate, decimal per sqrt(year)
+	Scenarios      int     // outer scenarios, the liability config's iTotalScenarios
+	InnerScenarios int     // scenarios per inner projection month, no inner files when 0
+	Seed           int64   // equal seeds give equal files
+	Antithetic     bool    // every second scenario mirrors the draws of the one before
+
+	// scenario files pALM already reads whose columns, delimiter, first scenario and month
+	// number and rate unit the files copy. when empty, the sOutterLoopScenario and
+	// sInnerLoopScenario files of the liability config at LiabilityConfigPath are taken,
+	// resolved as PreflightScenarioFiles resolves them from PalmFolderPath
+	OuterTemplatePath   string
+	InnerTemplatePath   string
+	LiabilityConfigPath string
+	PalmFolderPath      string
+}
+
+// GenerateStochasticScenarios simulates Hull-White one-factor short rates calibrated to the
+// spot curves of a scenario config and writes <run_id>_HW1F_<SOFR|TR>_<Outer|Inner>.csv next
+// to the deterministic files, with one row per scenario and month. SOFR and Treasury share the
+// random draws, each curve being fitted on its own spot rates. inner scenarios branch off the
+// base curve at each inner projection month
+func (a *App) GenerateStochasticScenarios(request StochasticScenarioRequest) (*ScenarioGenerationResult, error) {
+	started := time.Now()
+
+	if request.MeanReversion <= 0 {
+		return nil, fmt.Errorf("mean reversion must be positive, got %g", request.MeanReversion)
+	}
+	if request.Volatility < 0 {
+		return nil, fmt.Errorf("volatility must not be negative, got %g", request.Volatility)
+	}
+	if request.Scenarios <= 0 {
+		return nil, fmt.Errorf("number of scenarios must be positive, got %d", request.Scenarios)
+	}
+	if request.InnerScenarios < 0 {
+		return nil, fmt.Errorf("number of inner scenarios must not be negative, got %d", request.InnerScenarios)
+	}
+
+	config, err := a.ReadScenarioConfig(request.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.resolveScenarioTemplates(&request); err != nil {
+		runtime.LogError(a.ctx, "Error resolving scenario templates: "+err.Error())
+		return nil, err
+	}
+
+	result := &ScenarioGenerationResult{
+		RunID:        config.RunID,
+		ConfigPath:   request.ConfigPath,
+		OutputFolder: scenarioOutputFolder(request.ConfigPath, config),
+		Engine:       ScenarioEngineNative,
+		CreatedAt:    started.Format(time.RFC3339),
+		Stochastic:   &request,
+	}
+	if result.ConfigSHA256, err = fileSHA256(request.ConfigPath); err != nil {
+		return nil, err
+	}
+
+	progress := func(stage string, done int, total int, message string) {
+		runtime.EventsEmit(a.ctx, "scenarioProgress", ScenarioProgress{RunID: config.RunID, Stage: stage, Done: done, Total: total, Message: message})
+	}
+
+	if err := generateStochasticFiles(config, request, result, progress); err != nil {
+		runtime.LogError(a.ctx, "Error generating stochastic scenarios: "+err.Error())
+		progress("failed", 0, 0, err.Error())
+		return nil, err
+	}
+
+	if err := a.finishScenarioGeneration(result, started, progress); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func generateStochasticFiles(config *ScenarioConfig, request StochasticScenarioRequest, result *ScenarioGenerationResult, progress func(stage string, done int, total int, message string)) error {
+	if strings.TrimSpace(config.RunID) == "" {
+		return fmt.Errorf("run_id is empty")
+	}
+	if config.NumberOfYears <= 0 {
+		return fmt.Errorf("NumberOfYears must be positive, got %d", config.NumberOfYears)
+	}
+	if len(config.SofrSpotRate) == 0 || len(config.TrSpotRate) == 0 {
+		return fmt.Errorf("sofrSpotRate and trSpotRate need at least one tenor")
+	}
+	monthPerYear := config.MonthPerYear
+	if monthPerYear <= 0 {
+		monthPerYear = 12
+	}
+
+	progress("curves", 0, 0, "")
+	scale := rateScale(config.SofrSpotRate, config.TrSpotRate)
+	convention := curve.ParseConvention(config.RateConvention)
+
+	if err := os.MkdirAll(result.OutputFolder, 0755); err != nil {
+		return err
+	}
+
+	loops := []scenarioLoop{{
+		name:       "Outer",
+		months:     config.NumberOfYears * monthPerYear,
+		ufr:        config.UFROuter,
+		ufrStart:   config.UFROuterStartMonth,
+		projection: []int{0},
+	}}
+	templates := []string{request.OuterTemplatePath}
+	innerMonths, err := projectionMonths(config.ListOfInnerProjectionMonth)
+	if err != nil {
+		return err
+	}
+	if request.InnerScenarios > 0 && len(innerMonths) > 0 {
+		years := config.NumberOfYearsInner
+		if years <= 0 {
+			years = config.NumberOfYears
+		}
+		loops = append(loops, scenarioLoop{
+			name:       "Inner",
+			months:     years * monthPerYear,
+			ufr:        config.UFRInner,
+			ufrStart:   config.UFRInnerStartMonth,
+			projection: innerMonths,
+			inner:      true,
+		})
+		templates = append(templates, request.InnerTemplatePath)
+	}
+
+	curves := []struct {
+		name  string
+		rates map[string]float64
+	}{
+		{"SOFR", config.SofrSpotRate},
+		{"TR", config.TrSpotRate},
+	}
+
+	total := 0
+	for _, loop := range loops {
+		total += len(loop.projection)
+	}
+	done := 0
+
+	for l, loop := range loops {
+		scenarios := request.Scenarios
+		if loop.inner {
+			scenarios = request.InnerScenarios
+		}
+
+		// pALM reads the files in the layout of the files it already has, there is no layout
+		// of our own to fall back on
+		if templates[l] == "" {
+			return fmt.Errorf("no %s template: set %sTemplatePath or a liability config with %s", strings.ToLower(loop.name), loop.name, templateFields[loop.inner])
+		}
+		format, err := readScenarioFileFormat(templates[l], loop.inner)
+		if err != nil {
+			return err
+		}
+
+		models := make([]*hullWhite, len(curves))
+		writers := make([]*scenarioFileWriter, len(curves))
+		for i, c := range curves {
+			base, err := scenarioCurve(config, c.rates, scale, loop.ufr, loop.ufrStart, monthPerYear)
+			if err != nil {
+				return fmt.Errorf("%s spot rates: %w", c.name, err)
+			}
+			models[i] = &hullWhite{base: base, a: request.MeanReversion, sigma: request.Volatility}
+
+			path := filepath.Join(result.OutputFolder, config.RunID+"_"+stochasticShockName+"_"+c.name+"_"+loop.name+".csv")
+			if writers[i], err = newScenarioFileWriter(path, format); err != nil {
+				closeScenarioWriters(writers)
+				return err
+			}
+		}
+
+		for _, start := range loop.projection {
+			// each loop and projection month draws from its own stream, so inner sets neither
+			// replay the outer draws nor change with the other sets
+			rng := rand.New(rand.NewSource(streamSeed(request.Seed, l, start)))
+			paths := simulateShortRates(rng, request.MeanReversion, request.Volatility, scenarios, loop.months, monthPerYear, request.Antithetic)
+
+			for i, model := range models {
+				origin := float64(start) / float64(monthPerYear)
+				for scenario, x := range paths {
+					for month := 0; month <= loop.months; month++ {
+						t := float64(month) / float64(monthPerYear)
+						rates := make([]float64, len(format.tenors))
+						for k, tenor := range format.tenors {
+							rates[k] = curve.FromContinuous(model.zero(origin, t, tenor.years, x[month]), convention)
+						}
+						if err := writers[i].write(scenario, start, month, rates); err != nil {
+							closeScenarioWriters(writers)
+							return err
+						}
+					}
+				}
+			}
+			done++
+			progress(strings.ToLower(loop.name), done, total, fmt.Sprintf("projection month %d", start))
+		}
+
+		for i, writer := range writers {
+			if err := writer.close(); err != nil {
+				closeScenarioWriters(writers[i+1:])
+				return err
+			}
+			result.Files = append(result.Files, GeneratedScenarioFile{
+				Path:      writer.path,
+				Curve:     curves[i].name,
+				Loop:      strings.ToLower(loop.name),
+				Scenarios: scenarios,
+				Rows:      writer.rows,
+			})
+		}
+	}
+	return nil
+}
+
+// simulateShortRates draws the paths of x = r - alpha(t), the Ornstein-Uhlenbeck part of the
+// Hull-White short rate, starting at 0. the step is exact so paths don't depend on the
+// month length beyond their sampling
+func simulateShortRates(rng *rand.Rand, a float64, sigma float64, scenarios int, months int, monthPerYear int, antithetic bool) [][]float64 {
+	dt := 1 / float64(monthPerYear)
+	decay := math.Exp(-a * dt)
+	stdev := sigma * math.Sqrt((1-math.Exp(-2*a*dt))/(2*a))
+
+	paths := make([][]float64, scenarios)
+	for s := range paths {
+		x := make([]float64, months+1)
+		if antithetic && s%2 == 1 {
+			for m := 1; m <= months; m++ {
+				// mirror the previous scenario: its draws are (x[m] - decay*x[m-1]) / stdev
+				previous := paths[s-1]
+				x[m] = x[m-1]*decay - (previous[m] - previous[m-1]*decay)
+			}
+		} else {
+			for m := 1; m <= months; m++ {
+				x[m] = x[m-1]*decay + stdev*rng.NormFloat64()
+			}
+		}
+		paths[s] = x
+	}
+	return paths
+}
+
+// hullWhite prices zero coupon bonds under the Hull-White one-factor model fitted to base,
+// dr = (theta(t) - a r) dt + sigma dW
+type hullWhite struct {
+	base  *curve.Curve
+	a     float64
+	sigma float64
+}
+
+// zero is the continuously compounded zero rate for tenor years, t years after origin, given
+// the state x at t. the model is fitted to the base curve rolled forward to origin, so a model
+// started there with x = 0 reprices that curve
+func (hw *hullWhite) zero(origin float64, t float64, tenor float64, x float64) float64 {
+	if tenor <= 0 {
+		tenor = 1.0 / 365
+	}
+	logP := func(u float64) float64 {
+		return math.Log(hw.base.Discount(origin+u)) - math.Log(hw.base.Discount(origin))
+	}
+	b := (1 - math.Exp(-hw.a*tenor)) / hw.a
+	// ln P(t,T) = ln P(0,T)/P(0,t) + (V(t,T) - V(0,T) + V(0,t))/2 - B(t,T) x(t)
+	logPrice := logP(t+tenor) - logP(t) + (hw.variance(tenor)-hw.variance(t+tenor)+hw.variance(t))/2 - b*x
+	return -logPrice / tenor
+}
+
+// variance of the integral of x from 0 to tau when x starts at 0
+func (hw *hullWhite) variance(tau float64) float64 {
+	a := hw.a
+	return hw.sigma * hw.sigma / (a * a) * (tau + 2/a*math.Exp(-a*tau) - 1/(2*a)*math.Exp(-2*a*tau) - 3/(2*a))
+}
+
+// streamSeed derives the seed of one random stream from the request's seed and the stream's
+// keys, mixed with splitmix64 so neighbouring keys don't give related streams
+func streamSeed(seed int64, keys ...int) int64 {
+	x := uint64(seed)
+	for _, key := range keys {
+		x += 0x9e3779b97f4a7c15 * (uint64(key) + 1)
+		x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+		x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+		x ^= x >> 31
+	}
+	return int64(x)
+}
+
+// scenarioFileFormat is how stochastic rows are written: the columns and delimiter, the number
+// of the first scenario and month and the rate unit
+type scenarioFileFormat struct {
+	layout       *scenarioLayout
+	tenors       []scenarioTenor // of layout.tenors, in the same order
+	comma        rune
+	scenarioBase int
+	monthBase    int
+	scale        float64 // 100 for percent, 1 for decimals
+}
+
+// liability config fields the outer and inner templates default to
+var templateFields = map[bool]string{false: "sOutterLoopScenario", true: "sInnerLoopScenario"}
+
+// resolveScenarioTemplates fills the empty template paths of a request from its liability
+// config. a field that is empty or whose file isn't found leaves the path empty
+func (a *App) resolveScenarioTemplates(request *StochasticScenarioRequest) error {
+	if request.LiabilityConfigPath == "" || (request.OuterTemplatePath != "" && (request.InnerTemplatePath != "" || request.InnerScenarios == 0)) {
+		return nil
+	}
+	config, err := readLiabilityConfig(request.LiabilityConfigPath)
+	if err != nil {
+		return fmt.Errorf("reading liability config: %w", err)
+	}
+	roots, _ := a.scenarioFileRoots(request.PalmFolderPath)
+	if request.OuterTemplatePath == "" && config.SOutterLoopScenario != "" {
+		request.OuterTemplatePath, _ = resolveScenarioPath(config.SOutterLoopScenario, roots)
+	}
+	if request.InnerTemplatePath == "" && config.SInnerLoopScenario != "" {
+		request.InnerTemplatePath, _ = resolveScenarioPath(config.SInnerLoopScenario, roots)
+	}
+	return nil
+}
+
+// readScenarioFileFormat copies the format of an existing scenario file from its header and
+// first row. rates of more than 0.3 in that row are taken as percent
+func readScenarioFileFormat(path string, inner bool) (*scenarioFileFormat, error) {
+	it, err := newCSVIterator(path)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	layout, err := newScenarioLayout(path, it.Header())
+	if err != nil {
+		return nil, err
+	}
+	if inner && layout.projection < 0 {
+		return nil, fmt.Errorf("%s has no projection month column (%s)", path, strings.Join(projectionColumnNames, ", "))
+	}
+	format := &scenarioFileFormat{layout: layout, comma: it.comma, scenarioBase: 1, scale: 1}
+	for _, column := range layout.tenors {
+		years, err := curve.ParseTenor(layout.header[column])
+		if err != nil {
+			return nil, fmt.Errorf("%s: column %q is not a tenor: %w", path, layout.header[column], err)
+		}
+		format.tenors = append(format.tenors, scenarioTenor{label: layout.header[column], years: years})
+	}
+
+	if !it.Next() {
+		if err := it.Err(); err != nil {
+			return nil, csvLineError(path, err)
+		}
+		return nil, fmt.Errorf("%s has no rows to copy the numbering from", path)
+	}
+	row := it.Row()
+	if n, err := parseMonthCell(row[layout.scenario]); err == nil {
+		format.scenarioBase = n
+	}
+	if layout.month >= 0 {
+		if n, err := parseMonthCell(row[layout.month]); err == nil {
+			format.monthBase = n
+		}
+	}
+	for _, column := range layout.tenors {
+		if value, _, ok := parseNumber(row[column]); ok && math.Abs(value) > 0.3 {
+			format.scale = 100
+		}
+	}
+	return format, nil
+}
+
+// scenarioFileWriter streams rows in a scenarioFileFormat
+type scenarioFileWriter struct {
+	path   string
+	file   *os.File
+	writer *csv.Writer
+	format *scenarioFileFormat
+	rows   int
+}
+
+func newScenarioFileWriter(path string, format *scenarioFileFormat) (*scenarioFileWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &scenarioFileWriter{path: path, file: file, writer: csv.NewWriter(file), format: format}
+	w.writer.Comma = format.comma
+
+	if err := w.writer.Write(format.layout.header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
+
+// write adds the decimal rates of format.tenors for scenario and month, both counted from 0
+func (w *scenarioFileWriter) write(scenario int, projection int, month int, rates []float64) error {
+	layout := w.format.layout
+	row := make([]string, len(layout.header))
+	row[layout.scenario] = strconv.Itoa(scenario + w.format.scenarioBase)
+	if layout.shock >= 0 {
+		row[layout.shock] = stochasticShockName
+	}
+	if layout.projection >= 0 {
+		row[layout.projection] = strconv.Itoa(projection)
+	}
+	if layout.month >= 0 {
+		row[layout.month] = strconv.Itoa(month + w.format.monthBase)
+	}
+	for k, column := range layout.tenors {
+		row[column] = formatRate(rates[k] * w.format.scale)
+	}
+	w.rows++
+	if err := w.writer.Write(row); err != nil {
+		return fmt.Errorf("%s: %w", w.path, err)
+	}
+	return nil
+}
+
+func (w *scenarioFileWriter) close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("%s: %w", w.path, err)
+	}
+	return w.file.Close()
+}
+
+func closeScenarioWriters(writers []*scenarioFileWriter) {
+	for _, w := range writers {
+		if w != nil {
+			w.file.Close()
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"prismic-ui/curve"
+)
+
+func testHullWhite(t *testing.T) *hullWhite {
+	t.Helper()
+	base, err := curve.New([]float64{1, 2, 5, 10, 30}, []float64{0.03, 0.032, 0.035, 0.04, 0.042}, curve.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &hullWhite{base: base, a: 0.1, sigma: 0.01}
+}
+
+func TestHullWhiteZeroPathRepricesBase(t *testing.T) {
+	hw := testHullWhite(t)
+	for _, origin := range []float64{0, 1, 5} {
+		for _, tenor := range []float64{0.25, 1, 7, 20} {
+			got := math.Exp(-hw.zero(origin, 0, tenor, 0) * tenor)
+			want := hw.base.Discount(origin+tenor) / hw.base.Discount(origin)
+			if math.Abs(got-want) > 1e-12 {
+				t.Errorf("origin %g, tenor %g: price %.12f, want %.12f", origin, tenor, got, want)
+			}
+		}
+	}
+}
+
+// the expected discounted price of every bond the model prices must be today's price
+func TestHullWhiteMonteCarloDiscount(t *testing.T) {
+	hw := testHullWhite(t)
+	const (
+		monthPerYear = 12
+		years        = 10
+		scenarios    = 20000
+	)
+	paths := simulateShortRates(rand.New(rand.NewSource(7)), hw.a, hw.sigma, scenarios, years*monthPerYear, monthPerYear, true)
+
+	for _, horizon := range []int{1, 5, 10} {
+		months := horizon * monthPerYear
+		maturity := float64(horizon) + 5
+		// the integral of the deterministic part of r to the horizon, -ln P(0,t) + V(t)/2
+		drift := -math.Log(hw.base.Discount(float64(horizon))) + hw.variance(float64(horizon))/2
+
+		sum := 0.0
+		for _, x := range paths {
+			integral := 0.0
+			for m := 1; m <= months; m++ {
+				integral += (x[m-1] + x[m]) / 2 / monthPerYear
+			}
+			bond := math.Exp(-hw.zero(0, float64(horizon), maturity-float64(horizon), x[months]) * (maturity - float64(horizon)))
+			sum += math.Exp(-drift-integral) * bond
+		}
+		got := sum / scenarios
+		want := hw.base.Discount(maturity)
+		if math.Abs(got/want-1) > 0.002 {
+			t.Errorf("horizon %d: mean discounted price %.6f, want %.6f", horizon, got, want)
+		}
+	}
+}
+
+func testStochasticConfig() *ScenarioConfig {
+	return &ScenarioConfig{
+		RunID:                      "hw",
+		NumberOfYears:              2,
+		NumberOfYearsInner:         1,
+		MonthPerYear:               12,
+		TenorsOfInterests:          []string{"1Y", "5Y"},
+		ListOfInnerProjectionMonth: "0,12",
+		SofrSpotRate:               map[string]float64{"1Y": 3, "5Y": 3.5, "10Y": 4},
+		TrSpotRate:                 map[string]float64{"1Y": 2.8, "5Y": 3.3, "10Y": 3.9},
+	}
+}
+
+// pALM's files the generated ones copy the layout of, in percent
+func writeTestTemplates(t *testing.T, folder string) (string, string) {
+	t.Helper()
+	outer := filepath.Join(folder, "outer.csv")
+	inner := filepath.Join(folder, "inner.csv")
+	if err := os.WriteFile(outer, []byte("Scenario,Month,1Y,5Y\n1,0,3,3.5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(inner, []byte("Scenario,ProjectionMonth,Month,1Y,5Y\n1,0,0,3,3.5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return outer, inner
+}
+
+func generateTestFiles(t *testing.T, request StochasticScenarioRequest) map[string][]byte {
+	t.Helper()
+	if request.OuterTemplatePath == "" {
+		request.OuterTemplatePath, request.InnerTemplatePath = writeTestTemplates(t, t.TempDir())
+	}
+	result := &ScenarioGenerationResult{OutputFolder: t.TempDir()}
+	if err := generateStochasticFiles(testStochasticConfig(), request, result, func(string, int, int, string) {}); err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, file := range result.Files {
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filepath.Base(file.Path)] = data
+	}
+	return files
+}
+
+func TestStochasticFilesReproducible(t *testing.T) {
+	request := StochasticScenarioRequest{MeanReversion: 0.1, Volatility: 0.01, Scenarios: 4, InnerScenarios: 3, Seed: 42}
+	first := generateTestFiles(t, request)
+	second := generateTestFiles(t, request)
+
+	names := []string{"hw_HW1F_SOFR_Outer.csv", "hw_HW1F_TR_Outer.csv", "hw_HW1F_SOFR_Inner.csv", "hw_HW1F_TR_Inner.csv"}
+	if len(first) != len(names) {
+		t.Fatalf("got files %v, want %v", first, names)
+	}
+	for _, name := range names {
+		if _, ok := first[name]; !ok {
+			t.Fatalf("%s not written", name)
+		}
+		if !bytes.Equal(first[name], second[name]) {
+			t.Errorf("%s differs between runs with the same seed", name)
+		}
+	}
+
+	request.Seed = 43
+	if other := generateTestFiles(t, request); bytes.Equal(first["hw_HW1F_SOFR_Outer.csv"], other["hw_HW1F_SOFR_Outer.csv"]) {
+		t.Errorf("seeds 42 and 43 give the same outer file")
+	}
+}
+
+func TestStochasticInnerDrawsDifferFromOuter(t *testing.T) {
+	request := StochasticScenarioRequest{MeanReversion: 0.1, Volatility: 0.01, Scenarios: 2, InnerScenarios: 2, Seed: 1}
+	files := generateTestFiles(t, request)
+
+	// month 1 of the first scenario: outer row 2, inner row 2 (projection month 0)
+	outer := strings.Split(string(files["hw_HW1F_SOFR_Outer.csv"]), "\n")[2]
+	inner := strings.Split(string(files["hw_HW1F_SOFR_Inner.csv"]), "\n")[2]
+	outerRates := strings.Split(outer, ",")[2:]
+	innerRates := strings.Split(inner, ",")[3:]
+	if strings.Join(outerRates, ",") == strings.Join(innerRates, ",") {
+		t.Errorf("inner projection month 0 replays the outer draws: %s", inner)
+	}
+}
+
+func TestStochasticFilesCopyTemplate(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "outer.csv")
+	if err := os.WriteFile(template, []byte("Scen;t;5Y;1Y\n0;1;3.5;3.0\n0;2;3.5;3.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	request := StochasticScenarioRequest{MeanReversion: 0.1, Volatility: 0, Scenarios: 2, Seed: 1, OuterTemplatePath: template}
+	files := generateTestFiles(t, request)
+
+	lines := strings.Split(string(files["hw_HW1F_SOFR_Outer.csv"]), "\n")
+	if lines[0] != "Scen;t;5Y;1Y" {
+		t.Errorf("header %q, want the template's", lines[0])
+	}
+	cells := strings.Split(lines[1], ";")
+	if cells[0] != "0" || cells[1] != "1" {
+		t.Errorf("first row %q, want scenario 0 and month 1 as in the template", lines[1])
+	}
+	// no volatility: month 0 is the base curve, in percent as the template
+	if cells[3] != "3.0000000000" {
+		t.Errorf("1Y rate %s, want 3.0000000000", cells[3])
+	}
+}
+
+func TestStochasticTemplatesFromLiabilityConfig(t *testing.T) {
+	palmFolder := t.TempDir()
+	outer, inner := writeTestTemplates(t, palmFolder)
+	liabilityConfigPath := filepath.Join(palmFolder, "liability_config.json")
+	if err := os.WriteFile(liabilityConfigPath, []byte(`{"sOutterLoopScenario": "outer.csv", "sInnerLoopScenario": "inner.csv"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	request := StochasticScenarioRequest{InnerScenarios: 2, LiabilityConfigPath: liabilityConfigPath, PalmFolderPath: palmFolder}
+	if err := NewApp().resolveScenarioTemplates(&request); err != nil {
+		t.Fatal(err)
+	}
+	if request.OuterTemplatePath != outer || request.InnerTemplatePath != inner {
+		t.Errorf("templates %q and %q, want %q and %q", request.OuterTemplatePath, request.InnerTemplatePath, outer, inner)
+	}
+
+	// no template and no liability config to take one from
+	result := &ScenarioGenerationResult{OutputFolder: t.TempDir()}
+	request = StochasticScenarioRequest{MeanReversion: 0.1, Scenarios: 2, Seed: 1}
+	if err := generateStochasticFiles(testStochasticConfig(), request, result, func(string, int, int, string) {}); err == nil {
+		t.Errorf("no error without a template")
+	}
+}
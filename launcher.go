@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	stdruntime "runtime"
+	"strings"
+)
+
+const (
+	palmLauncherName = "pALMLauncher"
+
+	// where dotnet publishes the engine relative to the pALM project folder
+	palmReleaseDir = "bin/Release/net5.0"
+)
+
+// PalmLauncher is the resolved command used to start the pALM engine
+type PalmLauncher struct {
+	Path string   // executable that gets launched (pALMLauncher.exe, pALMLauncher or dotnet)
+	Args []string // arguments placed before the engine arguments (the dll when run through dotnet)
+	Dir  string   // directory holding the launcher, used as the working directory
+	Kind string   // exe, native or dotnet
+}
+
+// ResolvePalmLauncher finds the launcher to run for the current platform. path can be the
+// launcher itself, the folder holding it or a pALM project folder with a bin/Release/net5.0 build
+func (a *App) ResolvePalmLauncher(path string) (*PalmLauncher, error) {
+	launcher, err := resolvePalmLauncher(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &launcher, nil
+}
+
+func resolvePalmLauncher(path string) (PalmLauncher, error) {
+	if path == "" {
+		return PalmLauncher{}, fmt.Errorf("no pALM folder given")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return PalmLauncher{}, err
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return PalmLauncher{}, fmt.Errorf("pALM path %s: %w", path, err)
+	}
+
+	// pointing straight at a launcher file, pick the matching way to run it
+	if !info.IsDir() {
+		return launcherFromFile(absPath)
+	}
+
+	dirs := []string{absPath, filepath.Join(absPath, filepath.FromSlash(palmReleaseDir))}
+
+	var tried []string
+	for _, dir := range dirs {
+		for _, name := range palmLauncherCandidates() {
+			candidate := filepath.Join(dir, name)
+			tried = append(tried, candidate)
+
+			if info, err := os.Stat(candidate); err != nil || info.IsDir() {
+				continue
+			}
+
+			launcher, err := launcherFromFile(candidate)
+			if err != nil {
+				continue // e.g. dll present but dotnet is not installed
+			}
+			return launcher, nil
+		}
+	}
+
+	return PalmLauncher{}, fmt.Errorf("no pALM launcher found for %s, looked for: %s", stdruntime.GOOS, strings.Join(tried, ", "))
+}
+
+// palmLauncherCandidates lists launcher file names in order of preference for this OS.
+// the .exe apphost only runs on windows, elsewhere the native apphost or the dll is used
+func palmLauncherCandidates() []string {
+	if stdruntime.GOOS == "windows" {
+		return []string{palmLauncherName + ".exe", palmLauncherName + ".dll"}
+	}
+	return []string{palmLauncherName, palmLauncherName + ".dll"}
+}
+
+func launcherFromFile(path string) (PalmLauncher, error) {
+	dir := filepath.Dir(path)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dll":
+		dotnet, err := exec.LookPath("dotnet")
+		if err != nil {
+			return PalmLauncher{}, fmt.Errorf("%s needs the dotnet runtime, which was not found on PATH", filepath.Base(path))
+		}
+		return PalmLauncher{Path: dotnet, Args: []string{path}, Dir: dir, Kind: "dotnet"}, nil
+	case ".exe":
+		if stdruntime.GOOS != "windows" {
+			// the windows apphost can't run here, fall back to its dll if dotnet is around
+			dll := strings.TrimSuffix(path, filepath.Ext(path)) + ".dll"
+			if _, err := os.Stat(dll); err == nil {
+				return launcherFromFile(dll)
+			}
+			return PalmLauncher{}, fmt.Errorf("%s is a windows executable and no %s was found next to it", filepath.Base(path), filepath.Base(dll))
+		}
+		return PalmLauncher{Path: path, Dir: dir, Kind: "exe"}, nil
+	default:
+		return PalmLauncher{Path: path, Dir: dir, Kind: "native"}, nil
+	}
+}
+
+// command builds the engine command with the given arguments, run from the launcher folder
+func (l PalmLauncher) command(args ...string) *exec.Cmd {
+	cmdArgs := append(append([]string{}, l.Args...), args...)
+	cmd := exec.Command(l.Path, cmdArgs...)
+	cmd.Dir = l.Dir
+	setChildProcessAttributes(cmd)
+	return cmd
+}
+
+// rebaseConfigPath keeps a config path that was made relative to the folder the user picked
+// valid when the launcher was found in a subfolder of it (e.g. bin/Release/net5.0)
+func rebaseConfigPath(configPath string, pickedPath string, launcherDir string) string {
+	if configPath == "" || filepath.IsAbs(configPath) {
+		return configPath
+	}
+
+	pickedDir, err := filepath.Abs(pickedPath)
+	if err != nil {
+		return configPath
+	}
+	if info, err := os.Stat(pickedDir); err == nil && !info.IsDir() {
+		pickedDir = filepath.Dir(pickedDir)
+	}
+	if pickedDir == launcherDir {
+		return configPath
+	}
+
+	rel, err := filepath.Rel(launcherDir, filepath.Join(pickedDir, configPath))
+	if err != nil {
+		return configPath
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	stdruntime "runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// one manifest per run at the root of the output folder, output_manifest_<run>_<timestamp>.json.
+// output_manifest.json is what earlier versions wrote for the whole folder
+const (
+	outputManifestPrefix = "output_manifest"
+	outputManifestName   = outputManifestPrefix + ".json"
+)
+
+// OutputManifest lists the files of one run in its output folder, written by WriteOutputManifest
+type OutputManifest struct {
+	RunName      string
+	OutputFolder string
+	StartedAt    string // RFC3339, files modified since belong to the run
+	CreatedAt    string
+	FileCount    int
+	TotalBytes   int64
+	Files        []ManifestFile
+}
+
+// ManifestFile describes one output file. Rows and Columns are only set for tables
+type ManifestFile struct {
+	Path       string // relative to the output folder, slash separated
+	Name       string
+	SizeBytes  int64
+	SHA256     string
+	ModifiedAt string
+	Tabular    bool   // csv, tsv, txt or parquet
+	Rows       int    // data rows, header excluded
+	Columns    int    // columns of the header
+	Schema     string // recognised output schema, see knownTableSchemas
+	Error      string // set when the file couldn't be hashed or read as a table
+}
+
+// WriteOutputManifest scans a run's output folder (recursively) and writes
+// output_manifest_<run>_<timestamp>.json with the size, SHA-256, row and column counts and schema
+// of the run's files: those named after the run and, when runStartedAt (RFC3339) is given, those
+// modified since. the folder is shared by runs, other files are left out. writing again for the
+// same run and start replaces the manifest
+func (a *App) WriteOutputManifest(outputFolder string, runName string, runStartedAt string) (*OutputManifest, error) {
+	info, err := os.Stat(outputFolder)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading output folder: "+err.Error())
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a folder", outputFolder)
+	}
+
+	var started time.Time
+	if runStartedAt != "" {
+		if started, err = time.Parse(time.RFC3339, runStartedAt); err != nil {
+			return nil, fmt.Errorf("invalid run start %q: %w", runStartedAt, err)
+		}
+	}
+
+	var paths []string
+	err = filepath.WalkDir(outputFolder, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (filepath.Dir(path) == filepath.Clean(outputFolder) && isOutputManifest(d.Name())) {
+			return nil
+		}
+		if containsRunName(d.Name(), runName) {
+			paths = append(paths, path)
+			return nil
+		}
+		if started.IsZero() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().Before(started) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	created := time.Now()
+	stamp := created
+	if !started.IsZero() {
+		stamp = started
+	}
+	manifest := &OutputManifest{
+		RunName:      runName,
+		OutputFolder: outputFolder,
+		StartedAt:    runStartedAt,
+		CreatedAt:    created.Format(time.RFC3339),
+		FileCount:    len(paths),
+		Files:        make([]ManifestFile, len(paths)),
+	}
+
+	// hashing is IO bound, describe a few files at a time
+	workers := stdruntime.GOMAXPROCS(0)
+	if workers > maxParallelParses {
+		workers = maxParallelParses
+	}
+	semaphore := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			manifest.Files[i] = describeOutputFile(outputFolder, path)
+		}(i, path)
+	}
+	wg.Wait()
+
+	for _, file := range manifest.Files {
+		manifest.TotalBytes += file.SizeBytes
+		if file.Error != "" {
+			runtime.LogErrorf(a.ctx, "Manifest: %s: %s", file.Path, file.Error)
+		}
+	}
+
+	if err := writeJSONFile(filepath.Join(outputFolder, outputManifestFileName(runName, stamp)), manifest); err != nil {
+		runtime.LogError(a.ctx, "Error writing output manifest: "+err.Error())
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// ReadOutputManifest returns the latest manifest written in an output folder
+func (a *App) ReadOutputManifest(outputFolder string) (*OutputManifest, error) {
+	manifest, _, err := readRunManifest(outputFolder, "")
+	return manifest, err
+}
+
+func outputManifestFileName(runName string, stamp time.Time) string {
+	name := outputManifestPrefix
+	if runName != "" {
+		name += "_" + runName
+	}
+	return name + "_" + stamp.Format("20060102T150405") + ".json"
+}
+
+func isOutputManifest(name string) bool {
+	return name == outputManifestName || (strings.HasPrefix(name, outputManifestPrefix+"_") && strings.HasSuffix(name, ".json"))
+}
+
+// readRunManifest returns the latest manifest of runName in the folder, of any run when runName
+// is empty, with its path. fs.ErrNotExist when there is none
+func readRunManifest(outputFolder string, runName string) (*OutputManifest, string, error) {
+	entries, err := os.ReadDir(outputFolder)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var latest *OutputManifest
+	var latestPath string
+	var latestTime time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !isOutputManifest(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(outputFolder, entry.Name())
+		var manifest OutputManifest
+		if err := readJSON5File(path, &manifest); err != nil {
+			return nil, "", fmt.Errorf("reading %s: %w", entry.Name(), err)
+		}
+		if runName != "" && manifest.RunName != runName {
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339, manifest.CreatedAt)
+		if latest == nil || !created.Before(latestTime) {
+			latest, latestPath, latestTime = &manifest, path, created
+		}
+	}
+	if latest == nil {
+		return nil, "", fmt.Errorf("no output manifest in %s: %w", outputFolder, fs.ErrNotExist)
+	}
+	return latest, latestPath, nil
+}
+
+// containsRunName reports whether the run name is a whole token of a file name, see runNameIndex
+func containsRunName(name string, runName string) bool {
+	return runNameIndex(name, runName) >= 0
+}
+
+// runNameIndex is the index of the first occurrence of the run name in a file name or slash
+// separated path that is delimited by '_', '.', '/' or the ends, so my_run doesn't match my_run2's
+// files. -1 when there is none
+func runNameIndex(name string, runName string) int {
+	if runName == "" {
+		return -1
+	}
+	delimiter := func(c byte) bool { return c == '_' || c == '.' || c == '/' }
+	for offset := 0; ; {
+		i := strings.Index(name[offset:], runName)
+		if i < 0 {
+			return -1
+		}
+		start, end := offset+i, offset+i+len(runName)
+		if (start == 0 || delimiter(name[start-1])) && (end == len(name) || delimiter(name[end])) {
+			return start
+		}
+		offset = start + 1
+	}
+}
+
+// ReadRunFiles reads the CSV files of a run's output folder whose names contain filterString,
+// as ReadFiles does without walking, but takes them from the folder's latest manifest so other
+// runs' files are left out. files written after the manifest are read as well, and folders
+// without a manifest are listed as before
+func (a *App) ReadRunFiles(outputFolder string, filterString string) ([]CSVFile, error) {
+	return a.readRunFiles(outputFolder, ReadFilesOptions{Filter: filterString})
+}
+
+// ReadRunTables is ReadRunFiles returning typed tables, reading parquet outputs as well
+func (a *App) ReadRunTables(outputFolder string, filterString string) ([]OutputTable, error) {
+	files, err := a.readRunFiles(outputFolder, ReadFilesOptions{Filter: filterString, Formats: []string{".csv", ".parquet"}})
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]OutputTable, 0, len(files))
+	for _, file := range files {
+		tables = append(tables, newOutputTable(file))
+	}
+	return tables, nil
+}
+
+func (a *App) readRunFiles(outputFolder string, options ReadFilesOptions) ([]CSVFile, error) {
+	manifest, manifestPath, err := readRunManifest(outputFolder, "")
+	if errors.Is(err, fs.ErrNotExist) {
+		return a.readFiles(outputFolder, options, false)
+	}
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading output manifest: "+err.Error())
+		return nil, err
+	}
+
+	candidates, err := manifestCandidates(outputFolder, manifest, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// the folder's own listing for what the manifest doesn't list yet: files written after it.
+	// older unlisted files are earlier runs'
+	written, err := os.Stat(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	listed, err := selectFiles(outputFolder, options)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading directory"+err.Error())
+		return nil, err
+	}
+	recorded := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		recorded[candidate.path] = true
+	}
+	for _, candidate := range listed {
+		if recorded[candidate.path] {
+			continue
+		}
+		if info, err := os.Stat(candidate.path); err == nil && !info.ModTime().Before(written.ModTime()) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].path < candidates[j].path })
+
+	return a.readCandidates(candidates, false)
+}
+
+// manifestCandidates picks the manifest's files as selectFiles would pick them from the folder
+func manifestCandidates(outputFolder string, manifest *OutputManifest, options ReadFilesOptions) ([]fileCandidate, error) {
+	matcher, err := newFileMatcher(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []fileCandidate
+	for _, file := range manifest.Files {
+		if options.MaxDepth >= 0 && strings.Count(file.Path, "/") > options.MaxDepth {
+			continue
+		}
+		if !matcher.matches(file.Name, file.Path) {
+			continue
+		}
+		candidates = append(candidates, fileCandidate{path: filepath.Join(outputFolder, filepath.FromSlash(file.Path)), name: file.Name})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].path < candidates[j].path })
+
+	return candidates, nil
+}
+
+// RunOutputPath is the output path PostProcessRun takes for a module: the scenario 0 file of a
+// liability_analytics or risk_analytics run, which must be in the run's manifest, and the folder
+// otherwise. without a manifest for the run the file is taken as named
+func (a *App) RunOutputPath(module string, outputFolder string, runName string) (string, error) {
+	path := postProcessPath(module, outputFolder, runName)
+	if module != "liability_analytics" && module != "risk_analytics" {
+		return path, nil
+	}
+
+	manifest, _, err := readRunManifest(outputFolder, runName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return path, nil
+	}
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading output manifest: "+err.Error())
+		return "", err
+	}
+
+	// the exact name, other runs' files share the prefix
+	name := filepath.Base(path)
+	for _, file := range manifest.Files {
+		if file.Path == name {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not in the manifest of %s in %s", name, runName, outputFolder)
+}
+
+func describeOutputFile(root string, path string) ManifestFile {
+	file := ManifestFile{Path: path, Name: filepath.Base(path)}
+	if rel, err := filepath.Rel(root, path); err == nil {
+		file.Path = filepath.ToSlash(rel)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		file.Error = err.Error()
+		return file
+	}
+	file.SizeBytes = info.Size()
+	file.ModifiedAt = info.ModTime().Format(time.RFC3339)
+
+	if file.SHA256, err = fileSHA256(path); err != nil {
+		file.Error = err.Error()
+		return file
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, format := range tableFileFormats {
+		if ext == format {
+			file.Tabular = true
+		}
+	}
+	if !file.Tabular {
+		return file
+	}
+
+	if schema := detectTableSchema(file.Name); schema != nil {
+		file.Schema = schema.Name
+	}
+	if file.Rows, file.Columns, err = countTableShape(path); err != nil {
+		file.Error = err.Error()
+	}
+	return file
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// countTableShape returns the data rows and header columns of a table without keeping its rows
+func countTableShape(path string) (int, int, error) {
+	if strings.EqualFold(filepath.Ext(path), ".parquet") {
+		file, err := os.Open(path)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return 0, 0, err
+		}
+		pf, err := parquet.OpenFile(file, info.Size())
+		if err != nil {
+			return 0, 0, err
+		}
+		return int(pf.NumRows()), len(pf.Schema().Columns()), nil
+	}
+
+	it, err := newCSVIterator(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer it.Close()
+
+	rows := 0
+	for it.Next() {
+		rows++
+	}
+	if err := it.Err(); err != nil {
+		return 0, 0, fmt.Errorf("row %d: %w", rows+1, err)
+	}
+	return rows, len(it.Header()), nil
+}
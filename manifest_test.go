@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadRunFilesFollowsManifest(t *testing.T) {
+	folder := t.TempDir()
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(folder, name), []byte("Month,Value\n1,2\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// an earlier run's files in the shared folder
+	earlier := time.Now().Add(-time.Hour)
+	for _, name := range []string{"Parsed_LiabilityOutput_Scenario_9.csv", "DebugInfo_Scenario_my_run2_0.csv"} {
+		write(name)
+		if err := os.Chtimes(filepath.Join(folder, name), earlier, earlier); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	started := time.Now().Add(-time.Second).Format(time.RFC3339)
+	write("my_run_LiabilityOutput_Scenario_0.csv")
+	write("my_run_LiabilityOutput_Scenario_1.csv")
+	write("Parsed_LiabilityOutput_Scenario_0.csv")
+
+	app := NewApp()
+
+	// no manifest, the folder is listed
+	path, err := app.RunOutputPath("liability_analytics", folder, "my_run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(folder, "my_run_LiabilityOutput_Scenario_0.csv"); path != want {
+		t.Errorf("output path %s, want %s", path, want)
+	}
+	write("Parsed_LiabilityOutput_Scenario_1.csv")
+	files, err := app.ReadRunFiles(folder, "Parsed_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Errorf("read %d files without a manifest, want 3", len(files))
+	}
+
+	manifest, err := app.WriteOutputManifest(folder, "my_run", started)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.FileCount != 4 {
+		t.Errorf("manifest of %d files, want the run's 4: %+v", manifest.FileCount, manifest.Files)
+	}
+	// a second write for the run replaces its manifest
+	if _, err := app.WriteOutputManifest(folder, "my_run", started); err != nil {
+		t.Fatal(err)
+	}
+	manifests, err := filepath.Glob(filepath.Join(folder, "output_manifest_my_run_*.json"))
+	if err != nil || len(manifests) != 1 {
+		t.Errorf("manifests %v, %v", manifests, err)
+	}
+
+	// files written after the manifest are read, the earlier run's aren't
+	write("Parsed_LiabilityOutput_Scenario_2.csv")
+	files, err = app.ReadRunFiles(folder, "Parsed_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	if len(names) != 3 || names[0] != "Parsed_LiabilityOutput_Scenario_0.csv" || names[2] != "Parsed_LiabilityOutput_Scenario_2.csv" {
+		t.Errorf("read %v, want the run's three files", names)
+	}
+
+	tables, err := app.ReadRunTables(folder, "Scenario_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 {
+		t.Errorf("read %d tables, want 2", len(tables))
+	}
+
+	if _, err := app.RunOutputPath("risk_analytics", folder, "my_run"); err == nil {
+		t.Errorf("no error for a run the manifest has no risk output of")
+	}
+	if path, err := app.RunOutputPath("valuation", folder, "my_run"); err != nil || path != folder+string(filepath.Separator) {
+		t.Errorf("valuation output path %q, %v", path, err)
+	}
+}
+
+func TestContainsRunName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"my_run_LiabilityOutput_Scenario_0.csv", true},
+		{"DebugInfo_Scenario_my_run_0.csv", true},
+		{"my_run.csv", true},
+		{"my_run2_LiabilityOutput_Scenario_0.csv", false},
+		{"DebugInfo_Scenario_my_run2_0.csv", false},
+		{"x_my_run2_my_run_0.csv", true},
+		{"dummy_runs.csv", false},
+	}
+	for _, test := range tests {
+		if got := containsRunName(test.name, "my_run"); got != test.want {
+			t.Errorf("containsRunName(%q) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
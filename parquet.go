@@ -0,0 +1,328 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// parquet groups sort their fields by name, the original column order is kept in this metadata key
+const parquetColumnsKey = "prismic.columns"
+
+// rows written to a parquet file per batch
+const parquetBatchRows = 1024
+
+// ParquetExport lists the files written by ExportRunToParquet
+type ParquetExport struct {
+	SourceFolder string
+	DestFolder   string
+	Files        []ParquetExportFile
+}
+
+type ParquetExportFile struct {
+	Source    string // CSV the file was converted from
+	Path      string
+	Rows      int
+	Columns   int
+	SizeBytes int64
+}
+
+// ExportRunToParquet converts every CSV output under outputFolder into a typed parquet file,
+// keeping the folder layout. destFolder defaults to a "parquet" folder next to the outputs
+func (a *App) ExportRunToParquet(outputFolder string, destFolder string) (*ParquetExport, error) {
+	if destFolder == "" {
+		destFolder = filepath.Join(outputFolder, "parquet")
+	}
+
+	candidates, err := selectFiles(outputFolder, ReadFilesOptions{MaxDepth: -1, Formats: []string{".csv"}})
+	if err != nil {
+		return nil, err
+	}
+
+	export := &ParquetExport{SourceFolder: outputFolder, DestFolder: destFolder}
+	for i, parsed := range a.parseFiles(candidates) {
+		source := candidates[i].path
+		if parsed.err != nil {
+			return nil, fmt.Errorf("%s: %w", source, parsed.err)
+		}
+
+		rel, err := filepath.Rel(outputFolder, source)
+		if err != nil {
+			return nil, err
+		}
+		target := filepath.Join(destFolder, strings.TrimSuffix(rel, filepath.Ext(rel))+".parquet")
+
+		table := newOutputTable(*parsed.file)
+		if err := writeParquetFile(target, &table); err != nil {
+			runtime.LogErrorf(a.ctx, "Error exporting %s to parquet: %v", source, err)
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+
+		info, err := os.Stat(target)
+		if err != nil {
+			return nil, err
+		}
+		export.Files = append(export.Files, ParquetExportFile{
+			Source:    source,
+			Path:      target,
+			Rows:      table.RowCount,
+			Columns:   len(table.Columns),
+			SizeBytes: info.Size(),
+		})
+	}
+
+	runtime.LogInfof(a.ctx, "Exported %d files to parquet in %s", len(export.Files), destFolder)
+	return export, nil
+}
+
+// parquetColumnKind is the physical type a table column is written with
+type parquetColumnKind int
+
+const (
+	parquetString parquetColumnKind = iota
+	parquetInt64
+	parquetDouble
+)
+
+func parquetKindOf(column *TableColumn) parquetColumnKind {
+	if column.Type != ColumnTypeNumber {
+		return parquetString
+	}
+	for _, v := range column.Values {
+		if math.IsNaN(v) {
+			continue
+		}
+		if math.IsInf(v, 0) || v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return parquetDouble
+		}
+	}
+	return parquetInt64
+}
+
+// writeParquetFile writes a table with one optional column per table column: whole numbers as
+// INT64, other numbers as DOUBLE and the rest as strings. blank cells are written as nulls
+func writeParquetFile(path string, table *OutputTable) error {
+	names := uniqueColumnNames(table.Headers)
+	kinds := make([]parquetColumnKind, len(table.Columns))
+
+	group := parquet.Group{}
+	for i := range table.Columns {
+		kinds[i] = parquetKindOf(&table.Columns[i])
+		switch kinds[i] {
+		case parquetInt64:
+			group[names[i]] = parquet.Optional(parquet.Int(64))
+		case parquetDouble:
+			group[names[i]] = parquet.Optional(parquet.Leaf(parquet.DoubleType))
+		default:
+			group[names[i]] = parquet.Optional(parquet.String())
+		}
+	}
+	schema := parquet.NewSchema(strings.TrimSuffix(table.Name, filepath.Ext(table.Name)), group)
+
+	// leaf index of each table column in the schema
+	leafIndex := make(map[string]int)
+	for i, path := range schema.Columns() {
+		leafIndex[path[0]] = i
+	}
+
+	order, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := parquet.NewWriter(file, schema,
+		parquet.Compression(&parquet.Snappy),
+		parquet.KeyValueMetadata(parquetColumnsKey, string(order)))
+
+	batch := make([]parquet.Row, 0, parquetBatchRows)
+	for row := 0; row < table.RowCount; row++ {
+		values := make(parquet.Row, len(table.Columns))
+		for col := range table.Columns {
+			leaf := leafIndex[names[col]]
+			value := parquetValue(&table.Columns[col], kinds[col], row)
+			definition := 1
+			if value.IsNull() {
+				definition = 0
+			}
+			values[leaf] = value.Level(0, definition, leaf)
+		}
+
+		batch = append(batch, values)
+		if len(batch) == parquetBatchRows {
+			if _, err := writer.WriteRows(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if _, err := writer.WriteRows(batch); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func parquetValue(column *TableColumn, kind parquetColumnKind, row int) parquet.Value {
+	switch kind {
+	case parquetInt64, parquetDouble:
+		v := cellNumber(column, row)
+		if math.IsNaN(v) {
+			return parquet.NullValue()
+		}
+		if kind == parquetInt64 {
+			return parquet.Int64Value(int64(v))
+		}
+		return parquet.DoubleValue(v)
+	}
+
+	if row >= len(column.Strings) || column.Strings[row] == "" {
+		return parquet.NullValue()
+	}
+	return parquet.ByteArrayValue([]byte(column.Strings[row]))
+}
+
+// uniqueColumnNames makes headers usable as parquet field names: blank ones are named
+// after their position and duplicates get a numeric suffix
+func uniqueColumnNames(headers []string) []string {
+	names := make([]string, len(headers))
+	seen := make(map[string]bool, len(headers))
+	for i, header := range headers {
+		name := strings.TrimSpace(header)
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		unique := name
+		for n := 2; seen[unique]; n++ {
+			unique = fmt.Sprintf("%s_%d", name, n)
+		}
+		seen[unique] = true
+		names[i] = unique
+	}
+	return names
+}
+
+// parseParquetFile reads a flat parquet file into rows of strings, the first row being the
+// header, so parquet outputs go through the same table code as CSVs
+func parseParquetFile(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	columns := pf.Schema().Columns()
+	header := make([]string, len(columns))
+	position := make([]int, len(columns)) // output column of each leaf
+	for i, path := range columns {
+		if len(path) != 1 {
+			return nil, fmt.Errorf("nested column %s is not supported", strings.Join(path, "."))
+		}
+		header[i] = path[0]
+		position[i] = i
+	}
+
+	// restore the order of the columns as they were written
+	if order, ok := pf.Lookup(parquetColumnsKey); ok {
+		var names []string
+		if err := json.Unmarshal([]byte(order), &names); err == nil && len(names) == len(columns) {
+			at := make(map[string]int, len(names))
+			for i, name := range names {
+				at[name] = i
+			}
+			reordered := make([]int, len(columns))
+			valid := true
+			for i, name := range header {
+				p, ok := at[name]
+				if !ok {
+					valid = false
+					break
+				}
+				reordered[i] = p
+			}
+			if valid {
+				position = reordered
+				header = names
+			}
+		}
+	}
+
+	data := make([][]string, 0, pf.NumRows()+1)
+	data = append(data, header)
+
+	reader := parquet.NewReader(pf)
+	defer reader.Close()
+
+	rows := make([]parquet.Row, parquetBatchRows)
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			record := make([]string, len(columns))
+			for _, value := range row {
+				record[position[value.Column()]] = parquetCellString(value)
+			}
+			data = append(data, record)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", len(data), err)
+		}
+	}
+
+	return data, nil
+}
+
+func parquetCellString(value parquet.Value) string {
+	if value.IsNull() {
+		return ""
+	}
+	switch value.Kind() {
+	case parquet.Boolean:
+		return strconv.FormatBool(value.Boolean())
+	case parquet.Int32:
+		return strconv.FormatInt(int64(value.Int32()), 10)
+	case parquet.Int64:
+		return strconv.FormatInt(value.Int64(), 10)
+	case parquet.Float:
+		return strconv.FormatFloat(float64(value.Float()), 'f', -1, 32)
+	case parquet.Double:
+		return strconv.FormatFloat(value.Double(), 'f', -1, 64)
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		return string(value.ByteArray())
+	}
+	return value.String()
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// post processing engines
+const (
+	PostProcessNative = "native"
+	PostProcessPython = "python"
+)
+
+// PostProcessResult lists the files written by PostProcessRun
+type PostProcessResult struct {
+	Module       string
+	OutputFolder string
+	Engine       string   // native or python
+	Files        []string // files written (native only)
+	Output       string   // script output (python only)
+}
+
+var (
+	// "Scenario_<n>" or "Scenario_<run name>_<n>" in pALM output names, n being the scenario
+	scenarioPartPattern = regexp.MustCompile(`(?i)(?:^|_)Scenario_(?:(.*)_)?(\d+)(?:_|$)`)
+
+	// files written by post processing, never taken as engine output
+	postProcessedPattern = regexp.MustCompile(`^(Parsed|Combined)_`)
+)
+
+// PostProcessRun builds the Parsed_* and Combined_Scenario_* files of a run. outputPath and
+// runName are the parameters resultMergeScenarios.py takes: the output folder, or for
+// liability_analytics and risk_analytics the scenario 0 file. the script runs instead of the
+// built in parser when the ui config sets usePythonParser
+func (a *App) PostProcessRun(module string, outputPath string, runName string) (*PostProcessResult, error) {
+	config, err := a.ReadUIConfig()
+	if err == nil && config.UsePythonParser && config.PythonParserScript != "" {
+		return a.postProcessWithPython(config, module, outputPath, runName)
+	}
+
+	result, err := postProcessNative(module, outputPath, runName)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error post processing "+module+" output: "+err.Error())
+		return nil, err
+	}
+
+	// the written files replace whatever was cached under these names
+	a.fileCache.invalidate(result.OutputFolder)
+	return result, nil
+}
+
+// postProcessNative writes the files the output pages read for a module:
+//
+//	valuation            Combined_Scenario_<output>.csv of every per scenario output
+//	liability_analytics  Parsed_LiabilityOutput_Scenario_<n>.csv, one row per metric, and Combined_Scenario_*
+//	risk_analytics       Parsed_Scenario_<n>.csv of DebugInfo_Scenario_<run>_<n>.csv, and Combined_Scenario_*
+//	saa                  Parsed_<name>.csv of every output, and Combined_Scenario_*
+func postProcessNative(module string, outputPath string, runName string) (*PostProcessResult, error) {
+	folder := outputPath
+	if info, err := os.Stat(outputPath); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		folder = filepath.Dir(outputPath)
+	}
+
+	var err error
+	result := &PostProcessResult{Module: module, OutputFolder: folder, Engine: PostProcessNative}
+	switch module {
+	case "valuation":
+		err = combineScenarioFiles(folder, "", result)
+	case "liability_analytics":
+		marker := runName + "_LiabilityOutput_Scenario_"
+		if err = parseScenarioFiles(folder, marker, "Parsed_LiabilityOutput_Scenario_", transposeRows, result); err == nil {
+			err = combineScenarioFiles(folder, marker, result)
+		}
+	case "risk_analytics":
+		marker := "DebugInfo_Scenario_" + runName + "_"
+		if err = parseScenarioFiles(folder, marker, "Parsed_Scenario_", nil, result); err == nil {
+			err = combineScenarioFiles(folder, marker, result)
+		}
+	case "saa":
+		if err = parseFolderFiles(folder, result); err == nil {
+			err = combineScenarioFiles(folder, "", result)
+		}
+	default:
+		return nil, fmt.Errorf("unknown module %q", module)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// postProcessWithPython runs resultMergeScenarios.py the way the run page used to, with the
+// output path relative to the scripts folder
+func (a *App) postProcessWithPython(config *Config, module string, outputPath string, runName string) (*PostProcessResult, error) {
+	relPath := outputPath
+	if config.ScriptsFolderPath != "" {
+		if rel, err := filepath.Rel(config.ScriptsFolderPath, outputPath); err == nil {
+			relPath = filepath.ToSlash(rel)
+			// the script appends file names to folder paths
+			if strings.HasSuffix(outputPath, "/") || strings.HasSuffix(outputPath, `\`) {
+				relPath += "/"
+			}
+		}
+	}
+
+	output, err := a.ExecutePythonScript(config.PythonParserScript, []string{module, relPath, runName})
+	if err != nil {
+		return nil, err
+	}
+
+	folder := outputPath
+	if info, err := os.Stat(outputPath); err == nil && !info.IsDir() {
+		folder = filepath.Dir(outputPath)
+	}
+	a.fileCache.invalidate(folder)
+
+	return &PostProcessResult{Module: module, OutputFolder: folder, Engine: PostProcessPython, Output: output}, nil
+}
+
+// scenarioFile is an engine output file of one scenario
+type scenarioFile struct {
+	path     string
+	name     string
+	scenario int
+	group    string // file name without its scenario part, shared by every scenario of the output
+}
+
+// listScenarioFiles finds the per scenario engine outputs of a folder, those named marker<n>
+// when marker is set. the whole name is matched so another run's files, e.g. my_run2's for
+// my_run, are never taken
+func listScenarioFiles(folder string, marker string) ([]scenarioFile, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []scenarioFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".csv") || postProcessedPattern.MatchString(name) {
+			continue
+		}
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		if marker != "" {
+			if n, ok := strings.CutPrefix(base, marker); !ok || !isDigits(n) {
+				continue
+			}
+		}
+
+		loc := scenarioPartPattern.FindStringSubmatchIndex(base)
+		if loc == nil {
+			continue
+		}
+		scenario, err := strconv.Atoi(base[loc[4]:loc[5]])
+		if err != nil {
+			continue
+		}
+
+		// drop "Scenario_<n>" but keep the run name some outputs put in between
+		var parts []string
+		for _, part := range []string{base[:loc[0]], subgroup(base, loc, 1), base[loc[5]:]} {
+			if part = strings.Trim(part, "_"); part != "" {
+				parts = append(parts, part)
+			}
+		}
+
+		files = append(files, scenarioFile{
+			path:     filepath.Join(folder, name),
+			name:     name,
+			scenario: scenario,
+			group:    strings.Join(parts, "_"),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].group != files[j].group {
+			return files[i].group < files[j].group
+		}
+		return files[i].scenario < files[j].scenario
+	})
+	return files, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// subgroup returns submatch n of a FindStringSubmatchIndex result, empty when it didn't match
+func subgroup(s string, loc []int, n int) string {
+	if loc[2*n] < 0 {
+		return ""
+	}
+	return s[loc[2*n]:loc[2*n+1]]
+}
+
+// combineScenarioFiles stacks the scenarios of every output into Combined_Scenario_<output>.csv,
+// with a leading Scenario column
+func combineScenarioFiles(folder string, marker string, result *PostProcessResult) error {
+	files, err := listScenarioFiles(folder, marker)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string][]scenarioFile)
+	var order []string
+	for _, file := range files {
+		if _, ok := groups[file.group]; !ok {
+			order = append(order, file.group)
+		}
+		groups[file.group] = append(groups[file.group], file)
+	}
+
+	for _, group := range order {
+		members := groups[group]
+
+		var combined [][]string
+		for _, file := range members {
+			data, err := parseCSVFile(file.path)
+			if err != nil {
+				return err
+			}
+			if len(data) == 0 {
+				continue
+			}
+			if combined == nil {
+				combined = append(combined, append([]string{"Scenario"}, data[0]...))
+			} else if len(data[0]) != len(combined[0])-1 {
+				return fmt.Errorf("%s: %d columns, other scenarios have %d", file.name, len(data[0]), len(combined[0])-1)
+			}
+			scenario := strconv.Itoa(file.scenario)
+			for _, row := range data[1:] {
+				combined = append(combined, append([]string{scenario}, row...))
+			}
+		}
+		if combined == nil {
+			continue
+		}
+
+		target := filepath.Join(folder, "Combined_Scenario_"+group+".csv")
+		if err := writeCSVFile(target, combined); err != nil {
+			return err
+		}
+		result.Files = append(result.Files, target)
+	}
+
+	return nil
+}
+
+// parseScenarioFiles writes Parsed_<prefix><n>.csv for every scenario file named marker<n>,
+// reshaped by transform (kept as is when nil)
+func parseScenarioFiles(folder string, marker string, prefix string, transform func([][]string) [][]string, result *PostProcessResult) error {
+	files, err := listScenarioFiles(folder, marker)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no %s files in %s", strings.Trim(marker, "_"), folder)
+	}
+
+	for _, file := range files {
+		data, err := parseCSVFile(file.path)
+		if err != nil {
+			return err
+		}
+		if transform != nil {
+			data = transform(data)
+		}
+
+		target := filepath.Join(folder, prefix+strconv.Itoa(file.scenario)+".csv")
+		if err := writeCSVFile(target, data); err != nil {
+			return err
+		}
+		result.Files = append(result.Files, target)
+	}
+	return nil
+}
+
+// parseFolderFiles writes a cleaned Parsed_<name>.csv (header width, no trailing delimiters)
+// for every engine output of a folder
+func parseFolderFiles(folder string, result *PostProcessResult) error {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".csv") || postProcessedPattern.MatchString(name) {
+			continue
+		}
+
+		data, err := parseCSVFile(filepath.Join(folder, name))
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(folder, "Parsed_"+name)
+		if err := writeCSVFile(target, data); err != nil {
+			return err
+		}
+		result.Files = append(result.Files, target)
+	}
+	return nil
+}
+
+// transposeRows turns a monthly table (one row per month) into one row per metric, the
+// metric name first and then its value for each month
+func transposeRows(data [][]string) [][]string {
+	if len(data) == 0 {
+		return data
+	}
+
+	width := len(data[0])
+	transposed := make([][]string, width)
+	for col := 0; col < width; col++ {
+		row := make([]string, len(data))
+		for i, record := range data {
+			if col < len(record) {
+				row[i] = strings.TrimSpace(record[col])
+			}
+		}
+		transposed[col] = row
+	}
+	return transposed
+}
+
+func writeCSVFile(path string, data [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(data); err != nil {
+		file.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testdata/postprocess/<module> holds engine outputs and the Parsed_/Combined_Scenario_ files
+// the output pages read for them, the valuation page's Combined_Scenario_<run>_nestedinner_*
+// downloads among them
+func TestPostProcessNativeFixtures(t *testing.T) {
+	tests := []struct {
+		module     string
+		outputPath string // relative to the copied input folder, "" for the folder itself
+	}{
+		{"valuation", ""},
+		{"liability_analytics", "my_run_LiabilityOutput_Scenario_0.csv"},
+		{"risk_analytics", "DebugInfo_Scenario_my_run_0.csv"},
+		{"saa", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.module, func(t *testing.T) {
+			fixture := filepath.Join("testdata", "postprocess", test.module)
+			folder := t.TempDir()
+			copyTestFiles(t, filepath.Join(fixture, "input"), folder)
+
+			result, err := postProcessNative(test.module, filepath.Join(folder, test.outputPath), "my_run")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected, err := os.ReadDir(filepath.Join(fixture, "expected"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want, got []string
+			for _, entry := range expected {
+				want = append(want, entry.Name())
+			}
+			for _, path := range result.Files {
+				got = append(got, filepath.Base(path))
+			}
+			sort.Strings(want)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("wrote %v, want %v", got, want)
+			}
+
+			for _, name := range want {
+				wantData, err := os.ReadFile(filepath.Join(fixture, "expected", name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				gotData, err := os.ReadFile(filepath.Join(folder, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(gotData) != string(wantData) {
+					t.Errorf("%s:\n%s\nwant\n%s", name, gotData, wantData)
+				}
+			}
+		})
+	}
+}
+
+func copyTestFiles(t *testing.T, from string, to string) {
+	t.Helper()
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(from, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(to, entry.Name()), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
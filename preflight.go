@@ -0,0 +1,327 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"prismic-ui/curve"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// preflight issue severities, a run with errors would fail in pALM
+const (
+	PreflightError   = "error"
+	PreflightWarning = "warning"
+)
+
+// PreflightReport is the check of a liability config's scenario files before a run
+type PreflightReport struct {
+	Passed                 bool // no errors, warnings don't fail the check
+	RequiredScenarios      int  // iTotalScenarios
+	RequiredInnerScenarios int  // iInnerLoopScenariosNum
+	RequiredMonths         int  // estimated outer projection months, see requiredMonths
+	Files                  []PreflightFile
+	Issues                 []PreflightIssue
+}
+
+// PreflightFile is a scenario file setting of the liability config and what the file holds
+type PreflightFile struct {
+	Field        string // json name of the setting
+	Path         string // as set
+	ResolvedPath string // empty when the file wasn't found
+	Loop         string // outer or inner
+	Scenarios    int
+	Months       int // fewest months of any scenario path
+	Tenors       []string
+}
+
+type PreflightIssue struct {
+	Severity string
+	Field    string
+	Path     string
+	Message  string
+}
+
+// PreflightScenarioFiles opens the scenario files a liability config's flags make pALM read and
+// checks they have enough scenarios for iTotalScenarios (outer) and iInnerLoopScenariosNum
+// (inner), enough months for the projection and the same tenor columns as the other files of
+// their loop. relative paths are resolved against the launcher's folder, where pALM runs, then
+// palmFolderPath and the ui config's palmInputDataPath. a missing file, too few scenarios or
+// fewer months than an explicit iTimeStep is an error for the files pALM surely reads, see
+// preflightScenarioFiles, anything this check can't be sure of is a warning
+func (a *App) PreflightScenarioFiles(config LiabilityConfig, palmFolderPath string) (*PreflightReport, error) {
+	report := a.scenarioFilesReport(config, palmFolderPath)
+	for _, i := range report.Issues {
+		if i.Severity == PreflightError {
+			runtime.LogErrorf(a.ctx, "Preflight: %s (%s): %s", i.Field, i.Path, i.Message)
+		}
+	}
+	return report, nil
+}
+
+func (a *App) scenarioFilesReport(config LiabilityConfig, palmFolderPath string) *PreflightReport {
+	report := &PreflightReport{
+		RequiredScenarios:      config.ITotalScenarios,
+		RequiredInnerScenarios: config.IInnerLoopScenariosNum,
+		RequiredMonths:         requiredMonths(&config),
+		Files:                  []PreflightFile{},
+		Issues:                 []PreflightIssue{},
+	}
+
+	issue := func(severity string, file PreflightFile, format string, args ...interface{}) {
+		report.Issues = append(report.Issues, PreflightIssue{Severity: severity, Field: file.Field, Path: file.Path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	roots, err := a.scenarioFileRoots(palmFolderPath)
+	launcherResolved := err == nil
+	if err != nil && palmFolderPath != "" {
+		issue(PreflightWarning, PreflightFile{Field: "palmFolderPath", Path: palmFolderPath}, "relative paths not checked against the launcher's folder: %s", err.Error())
+	}
+
+	summaries := make(map[string]*ScenarioFileSummary)
+	failed := make(map[string]error)
+	tenorsByLoop := make(map[string]*PreflightFile) // first readable file of each loop
+
+	for _, ref := range preflightScenarioFiles(&config) {
+		file := PreflightFile{Field: ref.Field, Path: ref.Path, Loop: "outer"}
+		if strings.Contains(strings.ToLower(ref.Field), "inner") {
+			file.Loop = "inner"
+		}
+		severity := PreflightWarning
+		if ref.required {
+			severity = PreflightError
+		}
+
+		var root int
+		file.ResolvedPath, root = resolveScenarioPath(ref.Path, roots)
+		if file.ResolvedPath == "" {
+			issue(severity, file, "file not found")
+			report.Files = append(report.Files, file)
+			continue
+		}
+		// without the launcher there is no folder pALM is known to run in
+		if launcherResolved && root > 0 {
+			issue(PreflightWarning, file, "found under %s, not under %s where pALM runs", roots[root], roots[0])
+		}
+
+		summary, seen := summaries[file.ResolvedPath]
+		err := failed[file.ResolvedPath]
+		if !seen && err == nil {
+			if summary, err = readScenarioSummary(file.ResolvedPath); err != nil {
+				failed[file.ResolvedPath] = err
+			} else {
+				summaries[file.ResolvedPath] = summary
+			}
+		}
+		if err != nil {
+			// pALM may read layouts this check doesn't know, so only warn
+			issue(PreflightWarning, file, "couldn't check the file: %s", err.Error())
+			report.Files = append(report.Files, file)
+			continue
+		}
+
+		file.Scenarios = len(summary.Scenarios)
+		file.Months = summary.MinMonths
+		file.Tenors = summary.Tenors
+
+		required := report.RequiredScenarios
+		if file.Loop == "inner" {
+			required = report.RequiredInnerScenarios
+		}
+		if file.Scenarios < required {
+			issue(severity, file, "%d scenarios, the config needs %d", file.Scenarios, required)
+		}
+		// an explicit iTimeStep is the projection, otherwise requiredMonths is an estimate. inner
+		// paths only run to the inner horizon, which the liability config doesn't give
+		if file.Loop == "outer" && config.ITimeStep > 0 && file.Months < config.ITimeStep {
+			issue(severity, file, "%d months in the shortest scenario, iTimeStep is %d", file.Months, config.ITimeStep)
+		} else if file.Loop == "outer" && report.RequiredMonths > 0 && file.Months < report.RequiredMonths {
+			issue(PreflightWarning, file, "%d months in the shortest scenario, the projection looks like %d", file.Months, report.RequiredMonths)
+		}
+
+		var unknown []string
+		for _, tenor := range file.Tenors {
+			if _, err := curve.ParseTenor(tenor); err != nil {
+				unknown = append(unknown, tenor)
+			}
+		}
+		if len(unknown) > 0 {
+			issue(PreflightWarning, file, "columns %s aren't tenors", strings.Join(unknown, ", "))
+		}
+
+		if first, ok := tenorsByLoop[file.Loop]; !ok {
+			tenorsByLoop[file.Loop] = &file
+		} else if missing, extra := tenorDifference(first.Tenors, file.Tenors); len(missing) > 0 || len(extra) > 0 {
+			message := "tenor columns differ from " + first.Field
+			if len(missing) > 0 {
+				message += ", missing " + strings.Join(missing, ", ")
+			}
+			if len(extra) > 0 {
+				message += ", extra " + strings.Join(extra, ", ")
+			}
+			issue(PreflightWarning, file, "%s", message)
+		}
+
+		report.Files = append(report.Files, file)
+	}
+
+	report.Passed = true
+	for _, i := range report.Issues {
+		if i.Severity == PreflightError {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+type preflightFileRef struct {
+	scenarioFileRef
+	required bool // pALM surely reads it, so problems are errors
+}
+
+// preflightScenarioFiles picks the scenario files of liabilityScenarioFiles the config's flags
+// turn on: the outer and inner loop files, the sofr_* sets with bsofr_curve_swap or bswap_sofr,
+// the external sets with bload_scenarioapproch or bScenarioapproach_cf, and their liquidity
+// files only with brun_bma_liq_size. the files a flag turns on are required, the loop files
+// unless bOnTheFlyGenerator is set, the inner one being used only with inner scenarios
+func preflightScenarioFiles(config *LiabilityConfig) []preflightFileRef {
+	onTheFly := configFlag(config.BOnTheFlyGenerator)
+	sofr := configFlag(config.BsofrCurveSwap) || configFlag(config.BswapSofr)
+	external := config.BloadScenarioapproch || config.BScenarioapproachCf
+	liquidity := configFlag(config.BrunBmaLiqSize)
+
+	var refs []preflightFileRef
+	for _, ref := range liabilityScenarioFiles(config) {
+		field := strings.ToLower(ref.Field)
+		used := true
+		switch {
+		case ref.Field == "sOutterLoopScenario":
+		case ref.Field == "sInnerLoopScenario":
+			used = config.IInnerLoopScenariosNum > 0
+		case strings.HasPrefix(field, "sofr_"):
+			used = sofr
+		case strings.HasSuffix(field, "_external") || strings.Contains(field, "_external_"):
+			used = external
+		}
+		if strings.Contains(field, "liq") {
+			used = used && liquidity
+		}
+		if !used {
+			continue
+		}
+
+		required := true
+		if ref.Field == "sOutterLoopScenario" || ref.Field == "sInnerLoopScenario" {
+			required = !onTheFly
+		}
+		refs = append(refs, preflightFileRef{scenarioFileRef: ref, required: required})
+	}
+	return refs
+}
+
+// configFlag reads the loosely typed flags of the liability config, which the config files set
+// as booleans, 0/1 or strings
+func configFlag(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int:
+		return v != 0
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "1", "yes":
+			return true
+		}
+	}
+	return false
+}
+
+// requiredMonths estimates the outer projection length in months: iTimeStep monthly steps, or
+// iSimulationLength years, whichever is longer. pALM's own horizon may differ, so files shorter
+// than this only get a warning unless iTimeStep itself is longer
+func requiredMonths(config *LiabilityConfig) int {
+	months := config.ITimeStep
+	if years := config.ISimulationLength * 12; years > months {
+		months = years
+	}
+	return months
+}
+
+// scenarioFileRoots are the folders relative scenario file paths of a liability config are
+// resolved against: the launcher's folder, where pALM runs, then palmFolderPath and the ui
+// config's palmInputDataPath. the error is why the launcher wasn't resolved, the other roots
+// are returned regardless
+func (a *App) scenarioFileRoots(palmFolderPath string) ([]string, error) {
+	roots := []string{}
+	launcher, launcherErr := resolvePalmLauncher(palmFolderPath)
+	if launcherErr == nil {
+		roots = append(roots, launcher.Dir)
+	}
+	if palmFolderPath != "" {
+		if info, err := os.Stat(palmFolderPath); err == nil && !info.IsDir() {
+			palmFolderPath = filepath.Dir(palmFolderPath)
+		}
+		roots = append(roots, palmFolderPath)
+	}
+	if ui, err := a.ReadUIConfig(); err == nil && ui.PalmInputDataPath != "" {
+		roots = append(roots, ui.PalmInputDataPath)
+	}
+	return roots, launcherErr
+}
+
+// resolveScenarioPath returns the first existing file among path itself (when absolute) and
+// path under each root, with the index of the root it was found under
+func resolveScenarioPath(path string, roots []string) (string, int) {
+	path = strings.TrimSpace(path)
+	if filepath.IsAbs(path) {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, 0
+		}
+		return "", -1
+	}
+	for i, root := range roots {
+		candidate := filepath.Join(root, path)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, i
+		}
+	}
+	return "", -1
+}
+
+// tenorDifference lists the tenors of want missing from got and the ones got has in addition,
+// tenors compared by their length so "12M" matches "1Y"
+func tenorDifference(want []string, got []string) ([]string, []string) {
+	key := func(tenor string) string {
+		if years, err := curve.ParseTenor(tenor); err == nil {
+			return fmt.Sprintf("%.6f", years)
+		}
+		return strings.ToLower(strings.TrimSpace(tenor))
+	}
+
+	gotKeys := make(map[string]bool)
+	for _, tenor := range got {
+		gotKeys[key(tenor)] = true
+	}
+	wantKeys := make(map[string]bool)
+	var missing, extra []string
+	for _, tenor := range want {
+		wantKeys[key(tenor)] = true
+		if !gotKeys[key(tenor)] {
+			missing = append(missing, tenor)
+		}
+	}
+	for _, tenor := range got {
+		if !wantKeys[key(tenor)] {
+			extra = append(extra, tenor)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPreflightScenarioFilesFollowFlags(t *testing.T) {
+	config := LiabilityConfig{
+		SOutterLoopScenario:             "outer.csv",
+		SInnerLoopScenario:              "inner.csv",
+		SScenarioOutterfileExternal:     "outer_external.csv",
+		SScenarioInnerfileUpLiqExternal: "inner_up_liq_external.csv",
+		SofrOuter:                       "sofr_outer.csv",
+		SofrInnerLiqup:                  "sofr_inner_liqup.csv",
+	}
+	fields := func() map[string]bool {
+		selected := make(map[string]bool)
+		for _, ref := range preflightScenarioFiles(&config) {
+			selected[ref.Field] = ref.required
+		}
+		return selected
+	}
+
+	if got := fields(); !reflect.DeepEqual(got, map[string]bool{"sOutterLoopScenario": true}) {
+		t.Errorf("no flags: %v", got)
+	}
+
+	config.IInnerLoopScenariosNum = 10
+	config.BsofrCurveSwap = float64(1)
+	config.BloadScenarioapproch = true
+	want := map[string]bool{"sOutterLoopScenario": true, "sInnerLoopScenario": true, "SScenario_outterfile_external": true, "sofr_outer": true}
+	if got := fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("sofr and external: %v, want %v", got, want)
+	}
+
+	config.BrunBmaLiqSize = "true"
+	config.BOnTheFlyGenerator = true
+	want = map[string]bool{"sOutterLoopScenario": false, "sInnerLoopScenario": false, "SScenario_outterfile_external": true,
+		"sScenario_innerfile_up_liq_external": true, "sofr_outer": true, "sofr_inner_liqup": true}
+	if got := fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("liquidity on the fly: %v, want %v", got, want)
+	}
+}
+
+func issuesByField(report *PreflightReport) map[string]string {
+	severities := make(map[string]string)
+	for _, issue := range report.Issues {
+		if severities[issue.Field] != PreflightError {
+			severities[issue.Field] = issue.Severity
+		}
+	}
+	return severities
+}
+
+func TestPreflightScenarioFilesResolveFromLauncher(t *testing.T) {
+	palmFolder := t.TempDir()
+	releaseDir := filepath.Join(palmFolder, filepath.FromSlash(palmReleaseDir))
+	if err := os.MkdirAll(releaseDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(releaseDir, palmLauncherCandidates()[0]), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := "Scenario,Month,1Y,10Y\n1,1,3,4\n1,2,3,4\n2,1,3,4\n2,2,3,4\n"
+	for path, data := range map[string]string{
+		filepath.Join(releaseDir, "outer.csv"):      scenarios,
+		filepath.Join(palmFolder, "inner.csv"):      scenarios,
+		filepath.Join(releaseDir, "sofr_outer.csv"): "Scenario,Month,1Y,10Y\n1,1,3,4\n1,2,3,4\n",
+	} {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := LiabilityConfig{
+		ITotalScenarios:        2,
+		IInnerLoopScenariosNum: 2,
+		ISimulationLength:      1, // 12 months, more than the files hold
+		SOutterLoopScenario:    "outer.csv",
+		SInnerLoopScenario:     "inner.csv",
+		SofrOuter:              "sofr_outer.csv",
+		BswapSofr:              true,
+	}
+	report := NewApp().scenarioFilesReport(config, palmFolder)
+	if len(report.Files) != 3 || report.Files[0].ResolvedPath != filepath.Join(releaseDir, "outer.csv") {
+		t.Fatalf("files %+v", report.Files)
+	}
+	// short estimated months, inner found outside the launcher folder, and the sofr file bswap_sofr
+	// turns on has one scenario of two
+	want := map[string]string{"sOutterLoopScenario": PreflightWarning, "sInnerLoopScenario": PreflightWarning, "sofr_outer": PreflightError}
+	if got := issuesByField(report); report.Passed || !reflect.DeepEqual(got, want) {
+		t.Errorf("issues %v, want %v: %v", got, want, report.Issues)
+	}
+
+	// an explicit iTimeStep longer than the files
+	config.ITimeStep = 12
+	config.BswapSofr = false
+	report = NewApp().scenarioFilesReport(config, palmFolder)
+	want = map[string]string{"sOutterLoopScenario": PreflightError, "sInnerLoopScenario": PreflightWarning}
+	if got := issuesByField(report); report.Passed || !reflect.DeepEqual(got, want) {
+		t.Errorf("with iTimeStep, issues %v, want %v: %v", got, want, report.Issues)
+	}
+}
+
+func TestPreflightScenarioFilesWithoutLauncher(t *testing.T) {
+	palmFolder := t.TempDir()
+	inputFolder := t.TempDir()
+	scenarios := "Scenario,Month,1Y\n1,1,3\n"
+	if err := os.WriteFile(filepath.Join(palmFolder, "outer.csv"), []byte(scenarios), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(inputFolder, "inner.csv"), []byte(scenarios), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// ReadUIConfig reads ui_config.json from the working directory
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uiFolder := t.TempDir()
+	if err := os.WriteFile(filepath.Join(uiFolder, "ui_config.json"), []byte(`{"palmInputDataPath": `+strconv.Quote(inputFolder)+`}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(uiFolder); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+
+	config := LiabilityConfig{ITotalScenarios: 1, IInnerLoopScenariosNum: 1, SOutterLoopScenario: "outer.csv", SInnerLoopScenario: "inner.csv"}
+	report := NewApp().scenarioFilesReport(config, palmFolder)
+	if len(report.Files) != 2 || report.Files[1].ResolvedPath != filepath.Join(inputFolder, "inner.csv") {
+		t.Fatalf("files %+v", report.Files)
+	}
+	// the launcher warning only, no folder pALM runs in to compare with
+	want := map[string]string{"palmFolderPath": PreflightWarning}
+	if got := issuesByField(report); !report.Passed || !reflect.DeepEqual(got, want) {
+		t.Errorf("issues %v, want %v: %v", got, want, report.Issues)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setChildProcessAttributes starts child processes in their own process group, so a
+// Ctrl+C in the terminal running the UI (wails dev) doesn't also kill the engine
+func setChildProcessAttributes(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+}
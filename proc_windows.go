@@ -0,0 +1,15 @@
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setChildProcessAttributes hides the console window that would otherwise pop up
+// for every child process in production builds
+func setChildProcessAttributes(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,       // This is the crucial flag for Windows
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW - Alternative/additional flag
+	}
+}
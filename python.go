@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	stdruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// PythonInterpreter describes the interpreter a script was (or will be) run with
+type PythonInterpreter struct {
+	Path    string   // executable that gets launched (python, py, ...)
+	Args    []string // arguments placed before the script path (e.g. "-3" for the py launcher)
+	Version string   // output of --version, e.g. "Python 3.11.4"
+	Source  string   // where it came from: script, path, venv, conda or discovered
+}
+
+// PythonInvocation is recorded for every ExecutePythonScript call
+type PythonInvocation struct {
+	ScriptPath  string
+	Params      []string
+	Interpreter PythonInterpreter
+	StartedAt   string // RFC3339
+	DurationMs  int64
+	Error       string
+}
+
+// maximum number of invocations kept in memory
+const maxPythonInvocations = 200
+
+// ResolvePythonInterpreter returns the interpreter that would be used to run the given script
+func (a *App) ResolvePythonInterpreter(scriptPath string) (*PythonInterpreter, error) {
+	config, err := a.ReadUIConfig()
+	if err != nil {
+		// no ui config means no overrides, fall back to discovery
+		config = nil
+	}
+
+	interpreter, err := a.resolvePythonInterpreter(config, scriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &interpreter, nil
+}
+
+// GetPythonInvocations returns the most recent python script invocations, oldest first
+func (a *App) GetPythonInvocations() []PythonInvocation {
+	a.pythonMu.Lock()
+	defer a.pythonMu.Unlock()
+
+	invocations := make([]PythonInvocation, len(a.pythonInvocations))
+	copy(invocations, a.pythonInvocations)
+	return invocations
+}
+
+func (a *App) recordPythonInvocation(invocation PythonInvocation) {
+	if a.ctx != nil {
+		runtime.LogInfof(a.ctx, "python script %s ran with %s (%s) in %dms",
+			invocation.ScriptPath, invocation.Interpreter.Path, invocation.Interpreter.Version, invocation.DurationMs)
+	}
+
+	a.pythonMu.Lock()
+	defer a.pythonMu.Unlock()
+
+	a.pythonInvocations = append(a.pythonInvocations, invocation)
+	if len(a.pythonInvocations) > maxPythonInvocations {
+		a.pythonInvocations = a.pythonInvocations[len(a.pythonInvocations)-maxPythonInvocations:]
+	}
+}
+
+// resolvePythonInterpreter picks the interpreter for a script in this order:
+// per-script override, explicit path, venv, conda env, then discovery on PATH
+func (a *App) resolvePythonInterpreter(config *Config, scriptPath string) (PythonInterpreter, error) {
+	var interpreter PythonInterpreter
+	var err error
+
+	switch {
+	case config != nil && config.PythonScriptInterpreters[filepath.Base(scriptPath)] != "":
+		interpreter, err = interpreterFromSpec(config.PythonScriptInterpreters[filepath.Base(scriptPath)])
+		interpreter.Source = "script"
+	case config != nil && config.PythonInterpreterPath != "":
+		interpreter, err = interpreterFromPath(config.PythonInterpreterPath)
+	case config != nil && config.PythonVenvPath != "":
+		interpreter, err = interpreterFromVenv(config.PythonVenvPath)
+	case config != nil && config.PythonCondaEnv != "":
+		interpreter, err = interpreterFromConda(config.PythonCondaEnv)
+	default:
+		return a.discoverPythonInterpreter()
+	}
+
+	if err != nil {
+		return PythonInterpreter{}, err
+	}
+
+	interpreter.Version, err = a.pythonVersion(interpreter)
+	if err != nil {
+		return PythonInterpreter{}, fmt.Errorf("python interpreter %s is not usable: %w", interpreter.Path, err)
+	}
+
+	return interpreter, nil
+}
+
+// interpreterFromSpec interprets a per-script override, which can be
+// "conda:<env>", a venv directory or a path to a python executable
+func interpreterFromSpec(spec string) (PythonInterpreter, error) {
+	if env, ok := strings.CutPrefix(spec, "conda:"); ok {
+		return interpreterFromConda(env)
+	}
+
+	if info, err := os.Stat(spec); err == nil && info.IsDir() {
+		return interpreterFromVenv(spec)
+	}
+
+	return interpreterFromPath(spec)
+}
+
+func interpreterFromPath(path string) (PythonInterpreter, error) {
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return PythonInterpreter{}, fmt.Errorf("python interpreter not found at %s: %w", path, err)
+	}
+
+	return PythonInterpreter{Path: resolved, Source: "path"}, nil
+}
+
+func interpreterFromVenv(venvPath string) (PythonInterpreter, error) {
+	path, err := pythonInPrefix(venvPath, true)
+	if err != nil {
+		return PythonInterpreter{}, fmt.Errorf("invalid virtualenv %s: %w", venvPath, err)
+	}
+
+	return PythonInterpreter{Path: path, Source: "venv"}, nil
+}
+
+func interpreterFromConda(env string) (PythonInterpreter, error) {
+	prefix, err := condaEnvPrefix(env)
+	if err != nil {
+		return PythonInterpreter{}, err
+	}
+
+	path, err := pythonInPrefix(prefix, false)
+	if err != nil {
+		return PythonInterpreter{}, fmt.Errorf("invalid conda env %s: %w", env, err)
+	}
+
+	return PythonInterpreter{Path: path, Source: "conda"}, nil
+}
+
+// pythonInPrefix finds the python executable inside a venv or conda prefix.
+// venvs keep it in Scripts/ on windows, conda envs keep it in the prefix root
+func pythonInPrefix(prefix string, isVenv bool) (string, error) {
+	var candidates []string
+	if stdruntime.GOOS == "windows" {
+		if isVenv {
+			candidates = []string{filepath.Join(prefix, "Scripts", "python.exe")}
+		} else {
+			candidates = []string{filepath.Join(prefix, "python.exe")}
+		}
+	} else {
+		candidates = []string{
+			filepath.Join(prefix, "bin", "python3"),
+			filepath.Join(prefix, "bin", "python"),
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no python executable found (looked for %s)", strings.Join(candidates, ", "))
+}
+
+// condaEnvPrefix resolves a conda env name (or prefix directory) to its prefix
+func condaEnvPrefix(env string) (string, error) {
+	if info, err := os.Stat(env); err == nil && info.IsDir() {
+		return env, nil
+	}
+
+	condaExe := os.Getenv("CONDA_EXE")
+	if condaExe == "" {
+		var err error
+		condaExe, err = exec.LookPath("conda")
+		if err != nil {
+			return "", fmt.Errorf("conda env %s requested but conda was not found", env)
+		}
+	}
+
+	cmd := exec.Command(condaExe, "info", "--json")
+	setChildProcessAttributes(cmd)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error running conda info: %w", err)
+	}
+
+	var info struct {
+		RootPrefix string   `json:"root_prefix"`
+		Envs       []string `json:"envs"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return "", fmt.Errorf("error decoding conda info: %w", err)
+	}
+
+	if env == "base" && info.RootPrefix != "" {
+		return info.RootPrefix, nil
+	}
+
+	for _, prefix := range info.Envs {
+		if filepath.Base(prefix) == env {
+			return prefix, nil
+		}
+	}
+
+	return "", fmt.Errorf("conda env %s not found", env)
+}
+
+// discoverPythonInterpreter looks for python3, the windows py launcher and
+// python on PATH, skipping the Windows Store stub which only opens the store
+func (a *App) discoverPythonInterpreter() (PythonInterpreter, error) {
+	candidates := []PythonInterpreter{
+		{Path: "python3"},
+		{Path: "py", Args: []string{"-3"}},
+		{Path: "python"},
+	}
+
+	var tried []string
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate.Path)
+		if err != nil {
+			continue
+		}
+		if isWindowsStoreStub(path) {
+			tried = append(tried, path+" (Windows Store stub)")
+			continue
+		}
+
+		candidate.Path = path
+		candidate.Source = "discovered"
+
+		version, err := a.pythonVersion(candidate)
+		if err != nil || !strings.HasPrefix(version, "Python 3") {
+			tried = append(tried, path)
+			continue
+		}
+		candidate.Version = version
+
+		return candidate, nil
+	}
+
+	if len(tried) > 0 {
+		return PythonInterpreter{}, fmt.Errorf("no usable python 3 interpreter found, tried: %s", strings.Join(tried, ", "))
+	}
+	return PythonInterpreter{}, fmt.Errorf("no python interpreter found on PATH, set pythonInterpreterPath in ui_config.json")
+}
+
+func isWindowsStoreStub(path string) bool {
+	return strings.Contains(strings.ToLower(filepath.ToSlash(path)), "/microsoft/windowsapps/")
+}
+
+// pythonVersion runs the interpreter with --version, results are cached per interpreter
+func (a *App) pythonVersion(interpreter PythonInterpreter) (string, error) {
+	key := strings.Join(append([]string{interpreter.Path}, interpreter.Args...), " ")
+
+	a.pythonMu.Lock()
+	version, ok := a.pythonVersions[key]
+	a.pythonMu.Unlock()
+	if ok {
+		return version, nil
+	}
+
+	args := append(append([]string{}, interpreter.Args...), "--version")
+	cmd := exec.Command(interpreter.Path, args...)
+	setChildProcessAttributes(cmd)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error running %s --version: %w", key, err)
+	}
+	version = strings.TrimSpace(string(output))
+
+	a.pythonMu.Lock()
+	if a.pythonVersions == nil {
+		a.pythonVersions = make(map[string]string)
+	}
+	a.pythonVersions[key] = version
+	a.pythonMu.Unlock()
+
+	return version, nil
+}
+
+func newPythonInvocation(scriptPath string, params []string, interpreter PythonInterpreter, started time.Time, err error) PythonInvocation {
+	invocation := PythonInvocation{
+		ScriptPath:  scriptPath,
+		Params:      params,
+		Interpreter: interpreter,
+		StartedAt:   started.Format(time.RFC3339),
+		DurationMs:  time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		invocation.Error = err.Error()
+	}
+	return invocation
+}
@@ -0,0 +1,594 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/yosuke-furukawa/json5/encoding/json5"
+)
+
+// layout of the regression suite folder:
+//
+//	baselines/<name>/baseline.json          RegressionBaseline
+//	baselines/<name>/liability_config.json  config the baseline was produced with
+//	baselines/<name>/outputs/               baseline outputs
+//	runs/<id>/report.json                   RegressionReport
+//	runs/<id>/<name>/                       config, outputs and engine log of a re-run
+const (
+	regressionBaselinesDir = "baselines"
+	regressionRunsDir      = "runs"
+	regressionBaselineFile = "baseline.json"
+	regressionReportFile   = "report.json"
+	regressionConfigName   = "liability_config.json"
+)
+
+// RegressionBaseline is a registered run whose outputs new engine builds must reproduce
+type RegressionBaseline struct {
+	Name             string
+	Description      string
+	CreatedAt        string
+	RunName          string // sFileName of the config
+	Module           string // valuation, liability_analytics, risk_analytics or saa, post processed as after a run
+	SourceConfigPath string // config the baseline was registered from
+	SourceOutputPath string
+	EnginePath       string           // engine build that produced the baseline
+	Files            ReadFilesOptions // outputs kept in the baseline and compared on re-runs
+	OutputFiles      []string         // kept outputs, relative to the baseline outputs folder
+	AbsTolerance     float64
+	RelTolerance     float64
+}
+
+// RegisterBaselineRequest describes a run to register as a baseline
+type RegisterBaselineRequest struct {
+	Name         string
+	Description  string
+	ConfigPath   string // liability_config.json of the run
+	OutputFolder string // outputs of the run
+	Module       string // module of the run, re-runs are post processed for it
+	EnginePath   string
+	Files        ReadFilesOptions
+	AbsTolerance float64
+	RelTolerance float64
+}
+
+// RegressionRunRequest selects the baselines to re-run and the engine build to run them with
+type RegressionRunRequest struct {
+	Baselines  []string // every baseline when empty
+	EnginePath string   // pALM launcher or folder, the ui config's palmFolderPath when empty
+}
+
+// RegressionResult is the outcome of re-running one baseline
+type RegressionResult struct {
+	Baseline     string
+	Passed       bool
+	Failures     []string // why the baseline failed
+	Error        string   // set when the engine or the comparison failed to run
+	OutputFolder string
+	LogPath      string
+	DurationMs   int64
+	Comparison   *OutputComparison
+}
+
+// RegressionReport is the pass/fail report of a suite run, stored under runs/<ID>
+type RegressionReport struct {
+	ID          string
+	StartedAt   string
+	DurationMs  int64
+	EnginePath  string
+	Passed      bool
+	PassedCount int
+	FailedCount int
+	Results     []RegressionResult
+}
+
+// RegressionProgress is emitted as "regressionProgress" while a suite runs
+type RegressionProgress struct {
+	ReportID string
+	Baseline string
+	Stage    string // running, comparing, passed, failed or the engine's stream (stdout, stderr)
+	Message  string
+}
+
+// only one suite runs at a time, they'd fight over the engine otherwise
+var regressionMu sync.Mutex
+
+// regressionSuitePath is the folder holding baselines and run history, from the ui config's
+// regressionSuitePath or a "regression" folder next to it
+func (a *App) regressionSuitePath() (string, error) {
+	if config, err := a.ReadUIConfig(); err == nil && config.RegressionSuitePath != "" {
+		return config.RegressionSuitePath, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "regression"), nil
+}
+
+func validBaselineName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("baseline name is empty")
+	}
+	if strings.ContainsAny(name, `/\:*?"<>|`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid baseline name %q", name)
+	}
+	return nil
+}
+
+// RegisterRegressionBaseline copies a run's config and selected outputs into the suite
+func (a *App) RegisterRegressionBaseline(request RegisterBaselineRequest) (*RegressionBaseline, error) {
+	if err := validBaselineName(request.Name); err != nil {
+		return nil, err
+	}
+
+	suite, err := a.regressionSuitePath()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(suite, regressionBaselinesDir, request.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("baseline %q already exists", request.Name)
+	}
+
+	var config LiabilityConfig
+	if err := readJSON5File(request.ConfigPath, &config); err != nil {
+		return nil, err
+	}
+
+	candidates, err := selectFiles(request.OutputFolder, request.Files)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no output files selected in %s", request.OutputFolder)
+	}
+
+	baseline := &RegressionBaseline{
+		Name:             request.Name,
+		Description:      request.Description,
+		CreatedAt:        time.Now().Format(time.RFC3339),
+		RunName:          config.SFileName,
+		Module:           request.Module,
+		SourceConfigPath: request.ConfigPath,
+		SourceOutputPath: request.OutputFolder,
+		EnginePath:       request.EnginePath,
+		Files:            request.Files,
+		AbsTolerance:     request.AbsTolerance,
+		RelTolerance:     request.RelTolerance,
+	}
+
+	err = func() error {
+		if err := copyFile(request.ConfigPath, filepath.Join(dir, regressionConfigName)); err != nil {
+			return err
+		}
+		for _, candidate := range candidates {
+			rel, err := filepath.Rel(request.OutputFolder, candidate.path)
+			if err != nil {
+				return err
+			}
+			if err := copyFile(candidate.path, filepath.Join(dir, "outputs", rel)); err != nil {
+				return err
+			}
+			baseline.OutputFiles = append(baseline.OutputFiles, filepath.ToSlash(rel))
+		}
+		return writeJSONFile(filepath.Join(dir, regressionBaselineFile), baseline)
+	}()
+	if err != nil {
+		os.RemoveAll(dir) // don't leave half a baseline behind
+		runtime.LogError(a.ctx, "Error registering baseline: "+err.Error())
+		return nil, err
+	}
+
+	return baseline, nil
+}
+
+// ListRegressionBaselines returns the registered baselines sorted by name
+func (a *App) ListRegressionBaselines() ([]RegressionBaseline, error) {
+	suite, err := a.regressionSuitePath()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(suite, regressionBaselinesDir))
+	if os.IsNotExist(err) {
+		return []RegressionBaseline{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	baselines := []RegressionBaseline{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		var baseline RegressionBaseline
+		path := filepath.Join(suite, regressionBaselinesDir, entry.Name(), regressionBaselineFile)
+		if err := readJSON5File(path, &baseline); err != nil {
+			runtime.LogErrorf(a.ctx, "Skipping baseline %s: %v", entry.Name(), err)
+			continue
+		}
+		baselines = append(baselines, baseline)
+	}
+	return baselines, nil
+}
+
+// DeleteRegressionBaseline removes a baseline, the reports that used it are kept
+func (a *App) DeleteRegressionBaseline(name string) error {
+	if err := validBaselineName(name); err != nil {
+		return err
+	}
+	suite, err := a.regressionSuitePath()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(suite, regressionBaselinesDir, name)
+	if _, err := os.Stat(filepath.Join(dir, regressionBaselineFile)); err != nil {
+		return fmt.Errorf("baseline %q not found", name)
+	}
+	return os.RemoveAll(dir)
+}
+
+// RunRegressionSuite re-runs baselines with an engine build, compares their outputs with
+// the baseline tolerances and stores the pass/fail report in the suite's run history
+func (a *App) RunRegressionSuite(request RegressionRunRequest) (*RegressionReport, error) {
+	if !regressionMu.TryLock() {
+		return nil, fmt.Errorf("a regression suite is already running")
+	}
+	defer regressionMu.Unlock()
+
+	suite, err := a.regressionSuitePath()
+	if err != nil {
+		return nil, err
+	}
+
+	enginePath := request.EnginePath
+	if enginePath == "" {
+		config, err := a.ReadUIConfig()
+		if err != nil {
+			return nil, err
+		}
+		enginePath = config.PalmFolderPath
+	}
+	if _, err := resolvePalmLauncher(enginePath); err != nil {
+		return nil, err
+	}
+
+	baselines, err := a.selectBaselines(request.Baselines)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	report := &RegressionReport{
+		ID:         started.Format("20060102-150405"),
+		StartedAt:  started.Format(time.RFC3339),
+		EnginePath: enginePath,
+		Passed:     true,
+	}
+	runDir := filepath.Join(suite, regressionRunsDir, report.ID)
+	if _, err := os.Stat(runDir); err == nil {
+		return nil, fmt.Errorf("regression run %s already exists", report.ID)
+	}
+
+	for _, baseline := range baselines {
+		result := a.runBaseline(report.ID, baseline, filepath.Join(suite, regressionBaselinesDir, baseline.Name), filepath.Join(runDir, baseline.Name), enginePath)
+		if result.Passed {
+			report.PassedCount++
+		} else {
+			report.FailedCount++
+			report.Passed = false
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.DurationMs = time.Since(started).Milliseconds()
+
+	if err := writeJSONFile(filepath.Join(runDir, regressionReportFile), report); err != nil {
+		runtime.LogError(a.ctx, "Error writing regression report: "+err.Error())
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (a *App) selectBaselines(names []string) ([]RegressionBaseline, error) {
+	baselines, err := a.ListRegressionBaselines()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		if len(baselines) == 0 {
+			return nil, fmt.Errorf("no regression baselines registered")
+		}
+		return baselines, nil
+	}
+
+	byName := make(map[string]RegressionBaseline, len(baselines))
+	for _, baseline := range baselines {
+		byName[baseline.Name] = baseline
+	}
+
+	selected := make([]RegressionBaseline, 0, len(names))
+	for _, name := range names {
+		baseline, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("baseline %q not found", name)
+		}
+		selected = append(selected, baseline)
+	}
+	return selected, nil
+}
+
+// runBaseline runs the engine on a copy of the baseline config writing to dir/output, and
+// compares the result with the baseline outputs
+func (a *App) runBaseline(reportID string, baseline RegressionBaseline, baselineDir string, dir string, enginePath string) RegressionResult {
+	started := time.Now()
+	result := RegressionResult{
+		Baseline:     baseline.Name,
+		OutputFolder: filepath.Join(dir, "output"),
+		LogPath:      filepath.Join(dir, "palm.log"),
+	}
+	progress := func(stage string, message string) {
+		runtime.EventsEmit(a.ctx, "regressionProgress", RegressionProgress{ReportID: reportID, Baseline: baseline.Name, Stage: stage, Message: message})
+	}
+	fail := func(err error) RegressionResult {
+		result.Error = err.Error()
+		result.Failures = append(result.Failures, err.Error())
+		result.DurationMs = time.Since(started).Milliseconds()
+		progress("failed", err.Error())
+		return result
+	}
+
+	configDir := filepath.Join(dir, "config")
+	if err := writeRegressionConfig(filepath.Join(baselineDir, regressionConfigName), filepath.Join(configDir, regressionConfigName), baselineDir, result.OutputFolder); err != nil {
+		return fail(err)
+	}
+	if err := os.MkdirAll(result.OutputFolder, 0755); err != nil {
+		return fail(err)
+	}
+
+	logFile, err := os.Create(result.LogPath)
+	if err != nil {
+		return fail(err)
+	}
+	var logMu sync.Mutex
+	output := func(stream string, line string) {
+		logMu.Lock()
+		fmt.Fprintf(logFile, "[%s] %s\n", stream, line)
+		logMu.Unlock()
+		progress(stream, line)
+	}
+
+	progress("running", enginePath)
+	absConfigDir, err := filepath.Abs(configDir)
+	if err != nil {
+		logFile.Close()
+		return fail(err)
+	}
+	err = a.runPalm(enginePath, absConfigDir, regressionConfigName, output)
+	logFile.Close()
+	if err != nil {
+		return fail(fmt.Errorf("engine run failed: %w", err))
+	}
+
+	// the Parsed_* and Combined_Scenario_* files of the baseline come from post processing
+	if module := regressionModule(baseline); module != "" {
+		progress("post processing", module)
+		if _, err := a.PostProcessRun(module, postProcessPath(module, result.OutputFolder, baseline.RunName), baseline.RunName); err != nil {
+			return fail(fmt.Errorf("post processing failed: %w", err))
+		}
+	}
+
+	progress("comparing", "")
+	comparison, err := a.CompareOutputs(filepath.Join(baselineDir, "outputs"), result.OutputFolder, CompareOptions{
+		Files:        baseline.Files,
+		AbsTolerance: baseline.AbsTolerance,
+		RelTolerance: baseline.RelTolerance,
+	})
+	if err != nil {
+		return fail(fmt.Errorf("comparison failed: %w", err))
+	}
+	result.Comparison = comparison
+	result.Failures = regressionFailures(comparison)
+	result.Passed = len(result.Failures) == 0
+	result.DurationMs = time.Since(started).Milliseconds()
+
+	if result.Passed {
+		progress("passed", "")
+	} else {
+		progress("failed", strings.Join(result.Failures, "; "))
+	}
+	return result
+}
+
+// regressionFailures lists why a comparison fails: missing files, rows or columns, and
+// metrics outside the tolerances. new files in the re-run are not failures
+func regressionFailures(comparison *OutputComparison) []string {
+	var failures []string
+	for _, path := range comparison.OnlyInA {
+		failures = append(failures, "missing output "+filepath.Base(path))
+	}
+	for _, file := range comparison.Files {
+		if file.RowsOnlyA > 0 || file.RowsOnlyB > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %d baseline rows missing, %d new rows", file.Pattern, file.RowsOnlyA, file.RowsOnlyB))
+		}
+		if len(file.ColumnsOnlyA) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: missing columns %s", file.Pattern, strings.Join(file.ColumnsOnlyA, ", ")))
+		}
+	}
+	for _, metric := range comparison.Metrics {
+		if metric.CellsChanged > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s changed in %d of %d cells, max diff %g", metric.File, metric.Column, metric.CellsChanged, metric.CellsCompared, metric.MaxAbsDiff))
+		}
+	}
+	return failures
+}
+
+// regressionModule is the module a baseline is post processed for. baselines registered
+// without one are told by their engine outputs, none when those don't tell
+func regressionModule(baseline RegressionBaseline) string {
+	if baseline.Module != "" {
+		return baseline.Module
+	}
+	for _, file := range baseline.OutputFiles {
+		name := filepath.Base(file)
+		switch {
+		case postProcessedPattern.MatchString(name):
+			continue
+		case strings.Contains(name, "_LiabilityOutput_Scenario_"):
+			return "liability_analytics"
+		case strings.HasPrefix(name, "DebugInfo_Scenario_"):
+			return "risk_analytics"
+		}
+	}
+	return ""
+}
+
+// postProcessPath is the output path PostProcessRun takes for a module, as the run page passes
+// it: the scenario 0 file for liability_analytics and risk_analytics, the folder otherwise
+func postProcessPath(module string, folder string, runName string) string {
+	switch module {
+	case "liability_analytics":
+		return filepath.Join(folder, runName+"_LiabilityOutput_Scenario_0.csv")
+	case "risk_analytics":
+		return filepath.Join(folder, "DebugInfo_Scenario_"+runName+"_0.csv")
+	}
+	return folder + string(filepath.Separator)
+}
+
+// writeRegressionConfig writes the baseline config with its outputs redirected to outputDir.
+// bRegressionTest is left as the baseline has it; when set, the engine's own check is pointed
+// at the baseline outputs instead of wherever the original run compared to. the config is
+// kept as a generic object so fields the UI doesn't know about survive
+func writeRegressionConfig(source string, target string, baselineDir string, outputDir string) error {
+	var config map[string]interface{}
+	if err := readJSON5File(source, &config); err != nil {
+		return err
+	}
+
+	absOutput, err := filepath.Abs(outputDir)
+	if err != nil {
+		return err
+	}
+	absBaseline, err := filepath.Abs(filepath.Join(baselineDir, "outputs"))
+	if err != nil {
+		return err
+	}
+
+	// the engine appends file names straight to sCashPath, so it keeps its trailing slash
+	config["sCashPath"] = filepath.ToSlash(absOutput) + "/"
+	if enabled, _ := config["bRegressionTest"].(bool); enabled {
+		config["sRegressionPath"] = filepath.ToSlash(absBaseline) + "/"
+	}
+
+	return writeJSONFile(target, config)
+}
+
+// GetRegressionReports returns the run history of the suite, newest first, without the
+// comparison details
+func (a *App) GetRegressionReports() ([]RegressionReport, error) {
+	suite, err := a.regressionSuitePath()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(suite, regressionRunsDir))
+	if os.IsNotExist(err) {
+		return []RegressionReport{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []RegressionReport{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		report, err := a.GetRegressionReport(entry.Name())
+		if err != nil {
+			continue // run interrupted before its report was written
+		}
+		for i := range report.Results {
+			report.Results[i].Comparison = nil
+		}
+		reports = append(reports, *report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID > reports[j].ID })
+	return reports, nil
+}
+
+// GetRegressionReport returns a stored report with its comparisons
+func (a *App) GetRegressionReport(id string) (*RegressionReport, error) {
+	if err := validBaselineName(id); err != nil {
+		return nil, fmt.Errorf("invalid report id %q", id)
+	}
+	suite, err := a.regressionSuitePath()
+	if err != nil {
+		return nil, err
+	}
+
+	var report RegressionReport
+	if err := readJSON5File(filepath.Join(suite, regressionRunsDir, id, regressionReportFile), &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func readJSON5File(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := json5.NewDecoder(file).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// copyFile copies source to target, creating target's folder
+func copyFile(source string, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteRegressionConfigKeepsRegressionFlag(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		folder := t.TempDir()
+		source := filepath.Join(folder, "source.json")
+		config := `{"sFileName": "run", "sCashPath": "../out/", "sRegressionPath": "../old/", "bRegressionTest": false, "dblCustom": 1.5}`
+		if enabled {
+			config = `{"sFileName": "run", "sCashPath": "../out/", "sRegressionPath": "../old/", "bRegressionTest": true, "dblCustom": 1.5}`
+		}
+		if err := os.WriteFile(source, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		target := filepath.Join(folder, "config", "liability_config.json")
+		if err := writeRegressionConfig(source, target, filepath.Join(folder, "baseline"), filepath.Join(folder, "output")); err != nil {
+			t.Fatal(err)
+		}
+		var written map[string]interface{}
+		if err := readJSON5File(target, &written); err != nil {
+			t.Fatal(err)
+		}
+
+		if written["bRegressionTest"] != enabled {
+			t.Errorf("bRegressionTest %v, want the baseline's %v", written["bRegressionTest"], enabled)
+		}
+		wantRegressionPath := "../old/"
+		if enabled {
+			abs, _ := filepath.Abs(filepath.Join(folder, "baseline", "outputs"))
+			wantRegressionPath = filepath.ToSlash(abs) + "/"
+		}
+		if written["sRegressionPath"] != wantRegressionPath {
+			t.Errorf("sRegressionPath %v, want %s", written["sRegressionPath"], wantRegressionPath)
+		}
+		if written["dblCustom"] != 1.5 {
+			t.Errorf("unknown field lost: %v", written)
+		}
+	}
+}
+
+func TestRegressionModule(t *testing.T) {
+	tests := []struct {
+		baseline RegressionBaseline
+		module   string
+	}{
+		{RegressionBaseline{Module: "saa"}, "saa"},
+		{RegressionBaseline{OutputFiles: []string{"Parsed_LiabilityOutput_Scenario_0.csv", "my_run_LiabilityOutput_Scenario_0.csv"}}, "liability_analytics"},
+		{RegressionBaseline{OutputFiles: []string{"sub/DebugInfo_Scenario_my_run_0.csv"}}, "risk_analytics"},
+		{RegressionBaseline{OutputFiles: []string{"SBA_without_Equity_0.csv"}}, ""},
+	}
+	for _, test := range tests {
+		if got := regressionModule(test.baseline); got != test.module {
+			t.Errorf("%v: module %q, want %q", test.baseline.OutputFiles, got, test.module)
+		}
+	}
+}
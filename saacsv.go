@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var (
+	saaPeriodColumnNames = []string{"Period", "Rebalance", "Year"}
+
+	liabilityConfigVersionPattern = regexp.MustCompile(`^liability_config_(\d+)\.json$`)
+)
+
+// SAAImportRequest replaces the target portfolios of a liability config with spreadsheets
+type SAAImportRequest struct {
+	Config       LiabilityConfig
+	ConfigFolder string     // the new liability_config_<n>.json is written here
+	OuterPath    string     // CSV for SAA_target_port, kept as is when empty
+	InnerPath    string     // CSV for SAA_target_port_inner, kept as is when empty
+	Bounds       []SAABound // as for ValidateSAATargetPort
+	Normalise    bool       // rescale bad periods before writing
+}
+
+// SAAImportResult is the merged config, written only when its target portfolios pass the check
+type SAAImportResult struct {
+	Passed     bool
+	ConfigPath string // the new config version, empty when nothing was written
+	Config     LiabilityConfig
+	Files      []SAAImportFile
+	Report     *SAAValidationReport // check of the weights as imported
+}
+
+type SAAImportFile struct {
+	Path           string
+	Portfolio      string // outer or inner
+	Periods        int
+	Unit           string   // percent or fraction, as written in the file
+	UnknownColumns []string // not an asset class or the period, ignored
+	MissingColumns []string // asset classes without a column, set to 0
+}
+
+// ExportSAATargetPort writes SAA_target_port (portfolio outer) or SAA_target_port_inner (inner)
+// as CSV, a row per period and a column per asset class
+func (a *App) ExportSAATargetPort(config LiabilityConfig, portfolio string, path string) error {
+	periods := config.SAATargetPort
+	if strings.EqualFold(portfolio, SAAPortfolioInner) {
+		periods = config.SAATargetPortInner
+	} else if !strings.EqualFold(portfolio, SAAPortfolioOuter) {
+		return fmt.Errorf("unknown portfolio %q", portfolio)
+	}
+
+	rows := [][]string{append([]string{"Period"}, saaAssetClasses...)}
+	for i, port := range periods {
+		row := []string{strconv.Itoa(i + 1)}
+		for _, w := range saaWeights(port) {
+			row = append(row, strconv.FormatFloat(w, 'f', -1, 64))
+		}
+		rows = append(rows, row)
+	}
+
+	if err := writeCSVFile(path, rows); err != nil {
+		runtime.LogError(a.ctx, "Error exporting SAA target portfolio: "+err.Error())
+		return err
+	}
+	return nil
+}
+
+// ImportSAATargetPort reads SAA_target_port and SAA_target_port_inner from CSV files (a row
+// per period, a column per asset class), checks them with ValidateSAATargetPort and writes the
+// config with them as its next version. weights given in percent or as fractions are
+// converted to the unit the config already uses. only the portfolios imported decide whether
+// the import passes, the issues of the one kept as is are warnings
+func (a *App) ImportSAATargetPort(request SAAImportRequest) (*SAAImportResult, error) {
+	result, err := importSAATargetPort(request)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error importing SAA target portfolio: "+err.Error())
+		return nil, err
+	}
+	if !result.Passed {
+		runtime.LogErrorf(a.ctx, "SAA target portfolio check found %d issues", len(result.Report.Issues))
+		return result, nil
+	}
+
+	name, err := nextLiabilityConfigName(request.ConfigFolder)
+	if err != nil {
+		return nil, err
+	}
+	result.ConfigPath = filepath.Join(request.ConfigFolder, name)
+	if err := writeJSONFile(result.ConfigPath, result.Config); err != nil {
+		runtime.LogError(a.ctx, "Error writing liability config: "+err.Error())
+		return nil, err
+	}
+	runtime.LogInfof(a.ctx, "Imported SAA target portfolios into %s", result.ConfigPath)
+	return result, nil
+}
+
+// importSAATargetPort merges and checks the imported portfolios without writing the config
+func importSAATargetPort(request SAAImportRequest) (*SAAImportResult, error) {
+	if request.OuterPath == "" && request.InnerPath == "" {
+		return nil, fmt.Errorf("no SAA target portfolio file given")
+	}
+
+	result := &SAAImportResult{Config: request.Config, Files: []SAAImportFile{}}
+	imports := []struct {
+		portfolio string
+		path      string
+		target    *[]SAATargetPort
+	}{
+		{SAAPortfolioOuter, request.OuterPath, &result.Config.SAATargetPort},
+		{SAAPortfolioInner, request.InnerPath, &result.Config.SAATargetPortInner},
+	}
+	imported := make(map[string]bool)
+	for _, i := range imports {
+		if i.path == "" {
+			continue
+		}
+		periods, file, err := readSAATargetPortFile(i.path)
+		if err != nil {
+			return nil, err
+		}
+		file.Portfolio = i.portfolio
+
+		// keep the unit of the config's current weights
+		if len(*i.target) > 0 {
+			if unit := saaUnit(*i.target); unit != file.Unit {
+				factor := 100.0
+				if unit == "fraction" {
+					factor = 0.01
+				}
+				for p := range periods {
+					weights := saaWeights(periods[p])
+					for c := range weights {
+						weights[c] = math.Round(weights[c]*factor*1e10) / 1e10
+					}
+					periods[p] = saaTargetPort(weights)
+				}
+			}
+		}
+		*i.target = periods
+		imported[i.portfolio] = true
+		result.Files = append(result.Files, *file)
+	}
+
+	report, err := validateSAATargetPort(SAAValidationRequest{Config: result.Config, Bounds: request.Bounds, Normalise: request.Normalise})
+	if err != nil {
+		return nil, err
+	}
+	scopeSAAIssues(report, imported)
+	result.Report = report
+	result.Passed = report.Passed
+
+	if !report.Passed && request.Normalise {
+		// only the imported portfolios are replaced by their normalised weights
+		if imported[SAAPortfolioOuter] {
+			result.Config.SAATargetPort = report.SAATargetPort
+		}
+		if imported[SAAPortfolioInner] {
+			result.Config.SAATargetPortInner = report.SAATargetPortInner
+		}
+		final, err := validateSAATargetPort(SAAValidationRequest{Config: result.Config, Bounds: request.Bounds})
+		if err != nil {
+			return nil, err
+		}
+		scopeSAAIssues(final, imported)
+		result.Passed = final.Passed
+	}
+	return result, nil
+}
+
+// scopeSAAIssues turns the errors of the portfolios not imported into warnings, they are
+// in the config already and don't fail the import
+func scopeSAAIssues(report *SAAValidationReport, imported map[string]bool) {
+	report.Passed = true
+	for i, issue := range report.Issues {
+		if issue.Severity != PreflightError {
+			continue
+		}
+		if !imported[issue.Portfolio] {
+			report.Issues[i].Severity = PreflightWarning
+			report.Issues[i].Message += " (not imported)"
+			continue
+		}
+		report.Passed = false
+	}
+}
+
+// readSAATargetPortFile reads the periods of a target portfolio CSV. columns match the asset
+// classes by json or field name, ignoring case, spaces and underscores. rows are ordered by
+// the period column when there is one
+func readSAATargetPortFile(path string) ([]SAATargetPort, *SAAImportFile, error) {
+	data, err := parseCSVFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("%s is empty", path)
+	}
+	header := data[0]
+	file := &SAAImportFile{Path: path, Unit: "fraction", UnknownColumns: []string{}, MissingColumns: []string{}}
+
+	periodColumn := findHeaderColumn(header, saaPeriodColumnNames)
+	columns := make(map[int]int) // header column -> asset class
+	found := make(map[int]bool)
+	for c, name := range header {
+		if c == periodColumn || strings.TrimSpace(name) == "" {
+			continue
+		}
+		class := saaColumnClass(name)
+		if class < 0 {
+			file.UnknownColumns = append(file.UnknownColumns, name)
+			continue
+		}
+		if found[class] {
+			return nil, nil, fmt.Errorf("%s: asset class %s has two columns", path, saaAssetClasses[class])
+		}
+		found[class] = true
+		columns[c] = class
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("%s has no asset class columns (%s)", path, strings.Join(saaAssetClasses, ", "))
+	}
+	for class, name := range saaAssetClasses {
+		if !found[class] {
+			file.MissingColumns = append(file.MissingColumns, name)
+		}
+	}
+
+	type period struct {
+		number  int
+		weights []float64
+	}
+	var periods []period
+	percentSign := false
+	for r, row := range data[1:] {
+		blankRow := true
+		for _, value := range row {
+			if strings.TrimSpace(value) != "" {
+				blankRow = false
+				break
+			}
+		}
+		if blankRow {
+			continue
+		}
+
+		p := period{number: len(periods) + 1, weights: make([]float64, len(saaAssetClasses))}
+		if periodColumn >= 0 {
+			if p.number, err = parseMonthCell(row[periodColumn]); err != nil {
+				return nil, nil, fmt.Errorf("%s: row %d: invalid period %q", path, r+2, row[periodColumn])
+			}
+		}
+		for c, class := range columns {
+			value, blank, ok := parseNumber(row[c])
+			if !ok {
+				return nil, nil, fmt.Errorf("%s: row %d, column %s: invalid weight %q", path, r+2, header[c], row[c])
+			}
+			if blank {
+				continue
+			}
+			if strings.HasSuffix(strings.TrimSpace(row[c]), "%") {
+				percentSign = true
+			}
+			p.weights[class] = value
+		}
+		periods = append(periods, p)
+	}
+	if len(periods) == 0 {
+		return nil, nil, fmt.Errorf("%s has no periods", path)
+	}
+
+	if periodColumn >= 0 {
+		sort.SliceStable(periods, func(i, j int) bool { return periods[i].number < periods[j].number })
+		first := periods[0].number
+		if first != 0 && first != 1 {
+			return nil, nil, fmt.Errorf("%s: periods start at %d, not 0 or 1", path, first)
+		}
+		for i, p := range periods {
+			if p.number != first+i {
+				return nil, nil, fmt.Errorf("%s: period %d is missing or given twice", path, first+i)
+			}
+		}
+	}
+
+	result := make([]SAATargetPort, len(periods))
+	for i, p := range periods {
+		result[i] = saaTargetPort(p.weights)
+	}
+	file.Periods = len(result)
+	// "20%" cells are read as 0.2, so only plain numbers can be percent
+	if !percentSign {
+		file.Unit = saaUnit(result)
+	}
+	return result, file, nil
+}
+
+// saaColumnClass finds the asset class of a column by its json or Go field name
+func saaColumnClass(name string) int {
+	key := func(s string) string {
+		return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(s)))
+	}
+	portType := reflect.TypeOf(SAATargetPort{})
+	for i, class := range saaAssetClasses {
+		if key(name) == key(class) || key(name) == key(portType.Field(i).Name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextLiabilityConfigName is liability_config_<n>.json, n being one more than the highest
+// version in the folder, as the Run pALM page names new configs
+func nextLiabilityConfigName(folder string) (string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return "", err
+	}
+	version := 0
+	for _, entry := range entries {
+		if m := liabilityConfigVersionPattern.FindStringSubmatch(entry.Name()); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil && n > version {
+				version = n
+			}
+		}
+	}
+	return fmt.Sprintf("liability_config_%d.json", version+1), nil
+}
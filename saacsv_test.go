@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImportSAATargetPortChecksImportedPortfolios(t *testing.T) {
+	folder := t.TempDir()
+	write := func(name string, data string) string {
+		t.Helper()
+		path := filepath.Join(folder, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	outer := write("outer.csv", "Period,treasury,equity\n1,60,40\n2,70,30\n")
+	inner := write("inner.csv", "Period,treasury,equity\n1,60,30\n")
+
+	// the config's inner portfolio sums to 90%
+	config := LiabilityConfig{SAATargetPortInner: []SAATargetPort{{Treasury: 50, Equity: 40}}}
+
+	result, err := importSAATargetPort(SAAImportRequest{Config: config, OuterPath: outer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || !result.Report.Passed {
+		t.Errorf("outer import failed on %v", result.Report.Issues)
+	}
+	if len(result.Report.Issues) != 1 || result.Report.Issues[0].Portfolio != SAAPortfolioInner || result.Report.Issues[0].Severity != PreflightWarning {
+		t.Errorf("issues %v, want the inner sum as a warning", result.Report.Issues)
+	}
+	if len(result.Config.SAATargetPort) != 2 || result.Config.SAATargetPort[1].Equity != 30 {
+		t.Errorf("outer portfolio %v", result.Config.SAATargetPort)
+	}
+
+	// normalising leaves the portfolio not imported as it is
+	result, err = importSAATargetPort(SAAImportRequest{Config: config, OuterPath: outer, Normalise: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Config.SAATargetPortInner[0].Treasury != 50 {
+		t.Errorf("inner portfolio changed to %v", result.Config.SAATargetPortInner)
+	}
+
+	result, err = importSAATargetPort(SAAImportRequest{Config: config, InnerPath: inner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Errorf("inner import with weights summing to 90%% passed")
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SAA target portfolios of a liability config
+const (
+	SAAPortfolioOuter = "outer" // SAA_target_port
+	SAAPortfolioInner = "inner" // SAA_target_port_inner
+)
+
+// saaAssetClasses are the json names of the SAATargetPort weights, in declaration order
+var saaAssetClasses = func() []string {
+	portType := reflect.TypeOf(SAATargetPort{})
+	names := make([]string, portType.NumField())
+	for i := range names {
+		names[i] = strings.Split(portType.Field(i).Tag.Get("json"), ",")[0]
+	}
+	return names
+}()
+
+// SAABound limits the weight of an asset class as a fraction of a period's allocation,
+// 0.1 for 10%, whether the weights are in percent or fractions
+type SAABound struct {
+	AssetClass string   // json name, e.g. equity
+	Min        float64  // 0 for none
+	Max        *float64 // nil for none
+}
+
+// SAAValidationRequest checks the SAA target portfolios of a liability config
+type SAAValidationRequest struct {
+	Config    LiabilityConfig
+	Bounds    []SAABound // equity is bounded by dblMaxEquityExposure (dbl_inner_MaxEquityExposure inner) unless given
+	Tolerance float64    // allowed difference of a period's sum from 100%, as a fraction, 0.0001 when 0
+	Normalise bool       // rescale bad periods to 100% within the bounds
+}
+
+// SAAValidationReport lists the problems of the target portfolios, and the normalised
+// portfolios when asked for
+type SAAValidationReport struct {
+	Passed             bool
+	AssetClasses       []string
+	ExpectedPeriods    int // estimated from rebalance_time_schedual or irebalancefreq, 0 when neither is set
+	Portfolios         []SAAPortfolioCheck
+	Issues             []SAAIssue
+	SAATargetPort      []SAATargetPort // normalised SAA_target_port, nil unless Normalise is set
+	SAATargetPortInner []SAATargetPort // normalised SAA_target_port_inner, nil unless Normalise is set
+}
+
+type SAAPortfolioCheck struct {
+	Portfolio string // outer or inner
+	Field     string // json name
+	Unit      string // percent when periods sum to 100, fraction when they sum to 1
+	Periods   []SAAPeriodCheck
+}
+
+type SAAPeriodCheck struct {
+	Period     int // 1 for the first entry
+	Sum        float64
+	Valid      bool
+	Normalised bool
+}
+
+type SAAIssue struct {
+	Severity   string // error or warning, as for preflight
+	Portfolio  string
+	Period     int // 0 for the whole portfolio
+	AssetClass string
+	Message    string
+}
+
+// ValidateSAATargetPort checks every period of SAA_target_port and SAA_target_port_inner:
+// weights are non-negative, sum to 100% and keep within the asset class bounds. a period count
+// that differs from the rebalance schedule is only a warning. with Normalise set bad periods
+// are clipped to their bounds and rescaled, and the corrected portfolios returned
+func (a *App) ValidateSAATargetPort(request SAAValidationRequest) (*SAAValidationReport, error) {
+	report, err := validateSAATargetPort(request)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Passed {
+		runtime.LogErrorf(a.ctx, "SAA target portfolio check found %d issues", len(report.Issues))
+	}
+	return report, nil
+}
+
+func validateSAATargetPort(request SAAValidationRequest) (*SAAValidationReport, error) {
+	tolerance := request.Tolerance
+	if tolerance <= 0 {
+		tolerance = 0.0001
+	}
+
+	report := &SAAValidationReport{
+		AssetClasses:    saaAssetClasses,
+		ExpectedPeriods: expectedSAAPeriods(&request.Config),
+		Portfolios:      []SAAPortfolioCheck{},
+		Issues:          []SAAIssue{},
+	}
+
+	portfolios := []struct {
+		name       string
+		field      string
+		periods    []SAATargetPort
+		maxEquity  float64
+		normalised *[]SAATargetPort
+	}{
+		{SAAPortfolioOuter, "SAA_target_port", request.Config.SAATargetPort, request.Config.DblMaxEquityExposure, &report.SAATargetPort},
+		{SAAPortfolioInner, "SAA_target_port_inner", request.Config.SAATargetPortInner, request.Config.DblInnerMaxEquityExposure, &report.SAATargetPortInner},
+	}
+
+	for _, portfolio := range portfolios {
+		issue := func(severity string, period int, assetClass string, format string, args ...interface{}) {
+			report.Issues = append(report.Issues, SAAIssue{Severity: severity, Portfolio: portfolio.name, Period: period, AssetClass: assetClass, Message: fmt.Sprintf(format, args...)})
+		}
+
+		bounds, err := saaBounds(request.Bounds, portfolio.maxEquity)
+		if err != nil {
+			return nil, err
+		}
+
+		check := SAAPortfolioCheck{Portfolio: portfolio.name, Field: portfolio.field, Unit: saaUnit(portfolio.periods), Periods: []SAAPeriodCheck{}}
+		total := 1.0
+		if check.Unit == "percent" {
+			total = 100
+		}
+
+		if len(portfolio.periods) == 0 {
+			// inner portfolios are optional
+			if portfolio.name == SAAPortfolioOuter {
+				issue(PreflightError, 0, "", "%s has no periods", portfolio.field)
+			}
+			report.Portfolios = append(report.Portfolios, check)
+			continue
+		}
+		// the expected count is an estimate when it comes from irebalancefreq, so only warn
+		if expected := report.ExpectedPeriods; expected > 0 && len(portfolio.periods) != expected {
+			issue(PreflightWarning, 0, "", "%d periods, the rebalance schedule looks like %d", len(portfolio.periods), expected)
+		}
+
+		var normalised []SAATargetPort
+		for i, port := range portfolio.periods {
+			weights := saaWeights(port)
+			period := SAAPeriodCheck{Period: i + 1, Valid: true}
+			for _, w := range weights {
+				period.Sum += w
+			}
+
+			for c, w := range weights {
+				if math.IsNaN(w) || w < 0 {
+					issue(PreflightError, period.Period, saaAssetClasses[c], "negative weight %g", w)
+					period.Valid = false
+				}
+			}
+			if math.Abs(period.Sum-total) > tolerance*total {
+				issue(PreflightError, period.Period, "", "weights sum to %g, not %g", period.Sum, total)
+				period.Valid = false
+			}
+			for c, bound := range bounds {
+				if weights[c] < 0 {
+					continue // reported above
+				}
+				share := weights[c] / total
+				if share < bound.min-tolerance {
+					issue(PreflightError, period.Period, saaAssetClasses[c], "weight %g is below the minimum %g%%", weights[c], bound.min*100)
+					period.Valid = false
+				}
+				if share > bound.max+tolerance {
+					issue(PreflightError, period.Period, saaAssetClasses[c], "weight %g is above the maximum %g%%", weights[c], bound.max*100)
+					period.Valid = false
+				}
+			}
+
+			if request.Normalise && !period.Valid {
+				fixed, err := normaliseSAAWeights(weights, bounds, total)
+				if err != nil {
+					issue(PreflightError, period.Period, "", "can't normalise: %s", err.Error())
+				} else {
+					weights = fixed
+					period.Normalised = true
+				}
+			}
+			if request.Normalise {
+				normalised = append(normalised, saaTargetPort(weights))
+			}
+			check.Periods = append(check.Periods, period)
+		}
+		if request.Normalise {
+			*portfolio.normalised = normalised
+		}
+		report.Portfolios = append(report.Portfolios, check)
+	}
+
+	report.Passed = true
+	for _, i := range report.Issues {
+		if i.Severity == PreflightError {
+			report.Passed = false
+		}
+	}
+	return report, nil
+}
+
+// expectedSAAPeriods estimates the number of rebalances: the entries of rebalance_time_schedual,
+// or the projection divided into periods of irebalancefreq, taken as months
+func expectedSAAPeriods(config *LiabilityConfig) int {
+	if len(config.RebalanceTimeSchedual) > 0 {
+		return len(config.RebalanceTimeSchedual)
+	}
+	if months := requiredMonths(config); config.Irebalancefreq > 0 && months > 0 {
+		return (months + config.Irebalancefreq - 1) / config.Irebalancefreq
+	}
+	return 0
+}
+
+// saaUnit tells percent weights (periods summing to 100) from fractions (summing to 1)
+func saaUnit(periods []SAATargetPort) string {
+	for _, port := range periods {
+		sum := 0.0
+		for _, w := range saaWeights(port) {
+			sum += w
+		}
+		if sum > 1.5 {
+			return "percent"
+		}
+	}
+	return "fraction"
+}
+
+type saaBound struct {
+	min float64
+	max float64
+}
+
+// saaBounds is the bound of each asset class as fractions, 0 to 1 for unbounded classes.
+// maxEquity of more than 1 is taken as percent
+func saaBounds(bounds []SAABound, maxEquity float64) ([]saaBound, error) {
+	result := make([]saaBound, len(saaAssetClasses))
+	for i := range result {
+		result[i] = saaBound{min: 0, max: 1}
+	}
+
+	equity := saaAssetClassIndex("equity")
+	if maxEquity > 1 {
+		maxEquity /= 100
+	}
+	if maxEquity > 0 {
+		result[equity].max = maxEquity
+	}
+
+	for _, bound := range bounds {
+		i := saaAssetClassIndex(bound.AssetClass)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown asset class %q", bound.AssetClass)
+		}
+		result[i].min = bound.Min
+		result[i].max = 1
+		if bound.Max != nil {
+			result[i].max = *bound.Max
+		}
+		if result[i].min < 0 || result[i].max < result[i].min {
+			return nil, fmt.Errorf("%s: invalid bounds %g to %g", bound.AssetClass, result[i].min, result[i].max)
+		}
+	}
+	return result, nil
+}
+
+// normaliseSAAWeights clips negative weights to 0 and the weights to their bounds, then
+// rescales the weights within their bounds to sum to total, repeating until none is clipped
+func normaliseSAAWeights(weights []float64, bounds []saaBound, total float64) ([]float64, error) {
+	minSum, maxSum := 0.0, 0.0
+	for _, bound := range bounds {
+		minSum += bound.min
+		maxSum += bound.max
+	}
+	if minSum > 1+1e-12 || maxSum < 1-1e-12 {
+		return nil, fmt.Errorf("the asset class bounds allow no allocation summing to 100%%")
+	}
+
+	shares := make([]float64, len(weights))
+	for i, w := range weights {
+		if w > 0 && !math.IsNaN(w) {
+			shares[i] = w / total
+		}
+	}
+
+	fixed := make([]bool, len(shares))
+	for range shares {
+		free, fixedSum := 0.0, 0.0
+		for i, share := range shares {
+			if fixed[i] {
+				fixedSum += share
+			} else {
+				free += share
+			}
+		}
+		if free <= 0 {
+			break
+		}
+		factor := (1 - fixedSum) / free
+
+		clipped := false
+		for i := range shares {
+			if fixed[i] {
+				continue
+			}
+			shares[i] *= factor
+			if shares[i] > bounds[i].max {
+				shares[i], fixed[i], clipped = bounds[i].max, true, true
+			} else if shares[i] < bounds[i].min {
+				shares[i], fixed[i], clipped = bounds[i].min, true, true
+			}
+		}
+		if !clipped {
+			break
+		}
+	}
+
+	sum := 0.0
+	for _, share := range shares {
+		sum += share
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		return nil, fmt.Errorf("the weights within their bounds sum to %g%%", sum*100)
+	}
+
+	result := make([]float64, len(shares))
+	for i, share := range shares {
+		// rounded so a rescaled 100 doesn't print as 99.99999999999999
+		result[i] = math.Round(share*total*1e10) / 1e10
+	}
+	return result, nil
+}
+
+func saaAssetClassIndex(name string) int {
+	for i, class := range saaAssetClasses {
+		if strings.EqualFold(class, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// saaWeights lists the weights of a period in saaAssetClasses order
+func saaWeights(port SAATargetPort) []float64 {
+	value := reflect.ValueOf(port)
+	weights := make([]float64, value.NumField())
+	for i := range weights {
+		weights[i] = value.Field(i).Float()
+	}
+	return weights
+}
+
+func saaTargetPort(weights []float64) SAATargetPort {
+	var port SAATargetPort
+	value := reflect.ValueOf(&port).Elem()
+	for i, w := range weights {
+		value.Field(i).SetFloat(w)
+	}
+	return port
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var (
+	defaultFanPercentiles = []float64{5, 25, 50, 75, 95}
+
+	shockColumnNames      = []string{"Shock", "ShockName", "Shock_Name"}
+	projectionColumnNames = []string{"ProjectionMonth", "Projection_Month", "InnerProjectionMonth"}
+)
+
+// ScenarioFileSummary lists what a scenario file holds
+type ScenarioFileSummary struct {
+	Path             string
+	ScenarioColumn   string
+	MonthColumn      string // empty when months are the row order within a scenario
+	ProjectionColumn string // inner files: the outer month the inner scenarios branch off at
+	Tenors           []string
+	Scenarios        []ScenarioInfo // in scenario order
+	ProjectionMonths []int
+	Months           int // most months of any scenario path
+	MinMonths        int // fewest months of any scenario path
+	Rows             int
+}
+
+type ScenarioInfo struct {
+	Scenario string
+	Shock    string // shock name of deterministic scenarios
+	Months   int    // months of the scenario's (first) path
+}
+
+// ScenarioPathRequest picks a scenario and tenors of a scenario file
+type ScenarioPathRequest struct {
+	Path            string
+	Scenario        string
+	Tenors          []string  // every tenor when empty
+	ProjectionMonth int       // inner files only
+	Percentiles     []float64 // fan chart percentiles, 5 25 50 75 95 when empty
+}
+
+// ScenarioPaths are the monthly rates of one scenario, with percentiles across every scenario
+type ScenarioPaths struct {
+	Path            string
+	Scenario        string
+	Shock           string
+	ProjectionMonth int
+	ScenarioCount   int // scenarios the fan is computed over
+	Months          []int
+	Tenors          []TenorPath
+}
+
+type TenorPath struct {
+	Tenor  string
+	Values Float64Array // the scenario's rate at each of Months, NaN when missing
+	Fan    []FanBand
+}
+
+// FanBand is a percentile of the rate across scenarios at each month
+type FanBand struct {
+	Percentile float64
+	Values     Float64Array
+}
+
+// scenarioLayout is where the keys and rates are in a scenario file's header
+type scenarioLayout struct {
+	header     []string
+	scenario   int
+	month      int // -1 when missing
+	projection int // -1 when missing
+	shock      int // -1 when missing
+	tenors     []int
+}
+
+// scenarioRow is a data row of a scenario file with its keys read
+type scenarioRow struct {
+	scenario   string
+	shock      string
+	projection int
+	month      int
+	cells      []string
+}
+
+func findHeaderColumn(header []string, names []string) int {
+	for _, name := range names {
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func newScenarioLayout(path string, header []string) (*scenarioLayout, error) {
+	layout := &scenarioLayout{
+		header:     header,
+		scenario:   findHeaderColumn(header, scenarioNames),
+		projection: findHeaderColumn(header, projectionColumnNames),
+		shock:      findHeaderColumn(header, shockColumnNames),
+		month:      -1,
+	}
+	if layout.scenario < 0 {
+		return nil, fmt.Errorf("%s has no scenario column (%s)", path, strings.Join(scenarioNames, ", "))
+	}
+
+	var monthNames []string
+	for _, name := range timeColumnNames {
+		if findHeaderColumn([]string{name}, projectionColumnNames) < 0 {
+			monthNames = append(monthNames, name)
+		}
+	}
+	layout.month = findHeaderColumn(header, monthNames)
+
+	for i := range header {
+		if i != layout.scenario && i != layout.month && i != layout.projection && i != layout.shock {
+			layout.tenors = append(layout.tenors, i)
+		}
+	}
+	if len(layout.tenors) == 0 {
+		return nil, fmt.Errorf("%s has no rate columns", path)
+	}
+	return layout, nil
+}
+
+func (l *scenarioLayout) name(column int) string {
+	if column < 0 {
+		return ""
+	}
+	return l.header[column]
+}
+
+// scanScenarioFile streams the rows of a scenario file. without a month column the month is
+// the row's position in its scenario path
+func scanScenarioFile(path string, visit func(layout *scenarioLayout, row scenarioRow) error) (*scenarioLayout, error) {
+	it, err := newCSVIterator(path)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	layout, err := newScenarioLayout(path, it.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	type pathKey struct {
+		scenario   string
+		projection int
+	}
+	positions := make(map[pathKey]int)
+
+	for it.Next() {
+		cells := it.Row()
+		row := scenarioRow{scenario: strings.TrimSpace(cells[layout.scenario]), cells: cells}
+		if row.scenario == "" {
+			continue
+		}
+		if layout.shock >= 0 {
+			row.shock = strings.TrimSpace(cells[layout.shock])
+		}
+		if layout.projection >= 0 {
+			if row.projection, err = parseMonthCell(cells[layout.projection]); err != nil {
+				return nil, fmt.Errorf("%s: row %d: %s: %w", path, it.RowNum()+2, layout.name(layout.projection), err)
+			}
+		}
+		if layout.month >= 0 {
+			if row.month, err = parseMonthCell(cells[layout.month]); err != nil {
+				return nil, fmt.Errorf("%s: row %d: %s: %w", path, it.RowNum()+2, layout.name(layout.month), err)
+			}
+		} else {
+			key := pathKey{row.scenario, row.projection}
+			row.month = positions[key]
+			positions[key]++
+		}
+
+		if err := visit(layout, row); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, csvLineError(path, err)
+	}
+	return layout, nil
+}
+
+func parseMonthCell(cell string) (int, error) {
+	value, _, ok := parseNumber(cell)
+	if !ok || math.IsNaN(value) || value != math.Trunc(value) {
+		return 0, fmt.Errorf("invalid month %q", cell)
+	}
+	return int(value), nil
+}
+
+// ListScenarios reads a scenario file (the outer or inner sets of the liability config, or
+// files written by GenerateScenarios) and lists its scenarios, tenors and months
+func (a *App) ListScenarios(path string) (*ScenarioFileSummary, error) {
+	summary, err := readScenarioSummary(path)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading scenario file: "+err.Error())
+		return nil, err
+	}
+	return summary, nil
+}
+
+func readScenarioSummary(path string) (*ScenarioFileSummary, error) {
+	summary := &ScenarioFileSummary{Path: path}
+	infos := make(map[string]*ScenarioInfo)
+	projections := make(map[int]bool)
+	months := make(map[string]map[int]int) // scenario -> projection month -> months
+
+	layout, err := scanScenarioFile(path, func(layout *scenarioLayout, row scenarioRow) error {
+		summary.Rows++
+		info, ok := infos[row.scenario]
+		if !ok {
+			info = &ScenarioInfo{Scenario: row.scenario, Shock: row.shock}
+			infos[row.scenario] = info
+			months[row.scenario] = make(map[int]int)
+		}
+		projections[row.projection] = true
+		months[row.scenario][row.projection]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary.ScenarioColumn = layout.name(layout.scenario)
+	summary.MonthColumn = layout.name(layout.month)
+	summary.ProjectionColumn = layout.name(layout.projection)
+	for _, column := range layout.tenors {
+		summary.Tenors = append(summary.Tenors, layout.header[column])
+	}
+
+	names := make([]string, 0, len(infos))
+	for name := range infos {
+		names = append(names, name)
+	}
+	sortGroups(names)
+	for _, name := range names {
+		info := infos[name]
+		first := math.MaxInt
+		for projection, n := range months[name] {
+			if n > summary.Months {
+				summary.Months = n
+			}
+			if summary.MinMonths == 0 || n < summary.MinMonths {
+				summary.MinMonths = n
+			}
+			if projection < first {
+				first = projection
+				info.Months = n
+			}
+		}
+		summary.Scenarios = append(summary.Scenarios, *info)
+	}
+
+	if layout.projection >= 0 {
+		for projection := range projections {
+			summary.ProjectionMonths = append(summary.ProjectionMonths, projection)
+		}
+		sort.Ints(summary.ProjectionMonths)
+	}
+	return summary, nil
+}
+
+// ReadScenarioPaths returns the monthly rates of one scenario for the chosen tenors, and fan
+// chart percentiles of every tenor across all scenarios of the file
+func (a *App) ReadScenarioPaths(request ScenarioPathRequest) (*ScenarioPaths, error) {
+	if request.Scenario == "" {
+		return nil, fmt.Errorf("no scenario given")
+	}
+	percentiles := request.Percentiles
+	if len(percentiles) == 0 {
+		percentiles = defaultFanPercentiles
+	}
+	for _, p := range percentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile %g is outside 0-100", p)
+		}
+	}
+
+	result := &ScenarioPaths{Path: request.Path, Scenario: request.Scenario, ProjectionMonth: request.ProjectionMonth}
+
+	var columns []int                 // selected tenor columns
+	var selected map[int][]float64    // month -> rate per selected tenor
+	var all map[int][][]ScenarioValue // month -> values per selected tenor across scenarios
+	scenarios := make(map[string]bool)
+
+	layout, err := scanScenarioFile(request.Path, func(layout *scenarioLayout, row scenarioRow) error {
+		if columns == nil {
+			var err error
+			if columns, err = selectTenorColumns(layout, request.Tenors); err != nil {
+				return err
+			}
+			selected = make(map[int][]float64)
+			all = make(map[int][][]ScenarioValue)
+		}
+		if layout.projection >= 0 && row.projection != request.ProjectionMonth {
+			return nil
+		}
+		scenarios[row.scenario] = true
+
+		values, ok := all[row.month]
+		if !ok {
+			values = make([][]ScenarioValue, len(columns))
+			all[row.month] = values
+		}
+		rates := make([]float64, len(columns))
+		for i, column := range columns {
+			value, _, ok := parseNumber(row.cells[column])
+			if !ok {
+				value = math.NaN()
+			}
+			rates[i] = value
+			if !math.IsNaN(value) && !math.IsInf(value, 0) {
+				values[i] = append(values[i], ScenarioValue{Scenario: row.scenario, Value: value})
+			}
+		}
+
+		if row.scenario == request.Scenario {
+			selected[row.month] = rates
+			result.Shock = row.shock
+		}
+		return nil
+	})
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading scenario paths: "+err.Error())
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("scenario %s is not in %s", request.Scenario, request.Path)
+	}
+	result.ScenarioCount = len(scenarios)
+
+	for month := range all {
+		result.Months = append(result.Months, month)
+	}
+	sort.Ints(result.Months)
+
+	for _, values := range all {
+		for _, tenor := range values {
+			sort.Slice(tenor, func(a, b int) bool { return tenor[a].Value < tenor[b].Value })
+		}
+	}
+
+	for i, column := range columns {
+		path := TenorPath{Tenor: layout.header[column], Values: make(Float64Array, len(result.Months))}
+		for m, month := range result.Months {
+			path.Values[m] = math.NaN()
+			if rates, ok := selected[month]; ok {
+				path.Values[m] = rates[i]
+			}
+		}
+
+		for _, p := range percentiles {
+			band := FanBand{Percentile: p, Values: make(Float64Array, len(result.Months))}
+			for m, month := range result.Months {
+				values := all[month][i]
+				if len(values) == 0 {
+					band.Values[m] = math.NaN()
+					continue
+				}
+				band.Values[m] = percentile(values, p)
+			}
+			path.Fan = append(path.Fan, band)
+		}
+		result.Tenors = append(result.Tenors, path)
+	}
+	return result, nil
+}
+
+// selectTenorColumns finds the requested tenor columns, every rate column when none is requested
+func selectTenorColumns(layout *scenarioLayout, tenors []string) ([]int, error) {
+	if len(tenors) == 0 {
+		return layout.tenors, nil
+	}
+
+	columns := make([]int, 0, len(tenors))
+	for _, tenor := range tenors {
+		column := -1
+		for _, i := range layout.tenors {
+			if strings.EqualFold(strings.TrimSpace(layout.header[i]), strings.TrimSpace(tenor)) {
+				column = i
+				break
+			}
+		}
+		if column < 0 {
+			return nil, fmt.Errorf("no %s column", strconv.Quote(tenor))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
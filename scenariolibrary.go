@@ -0,0 +1,536 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// archived scenario configs are moved to this subfolder of scenarioConfigsPath
+const scenarioArchiveDir = "archive"
+
+// versioned scenario configs, as named by the Generate Inputs page
+var scenarioConfigVersionPattern = regexp.MustCompile(`^config_ESG_OTF_(\d+)\.json$`)
+
+// ScenarioConfigEntry is a scenario config of the library with the state of its outputs
+type ScenarioConfigEntry struct {
+	Name          string // file name
+	Path          string
+	RunID         string
+	Asof          string
+	OutputFolder  string // <output_path>/<run_id>
+	ScenarioFiles []string
+	FilesExist    bool     // every file of the last recorded generation is there, or the files the config names without a record
+	MissingFiles  []string // expected names that aren't
+	ReferencedBy  []string // liability configs using files of its output folder
+	Archived      bool
+	Base          bool // the ui config's baseScenarioConfigPath
+	ModifiedAt    string
+	Error         string // set when the config couldn't be read
+}
+
+// ScenarioConfigDetails is a library entry with its config and last generation
+type ScenarioConfigDetails struct {
+	ScenarioConfigEntry
+	Config     *ScenarioConfig
+	Generation *ScenarioGenerationResult // nil until generated natively
+}
+
+// scenarioConfigsFolder is folderPath, or the ui config's scenarioConfigsPath when empty
+func (a *App) scenarioConfigsFolder(folderPath string) (string, error) {
+	if folderPath != "" {
+		return folderPath, nil
+	}
+	config, err := a.ReadUIConfig()
+	if err != nil {
+		return "", err
+	}
+	if config.ScenarioConfigsPath == "" {
+		return "", fmt.Errorf("scenarioConfigsPath is not set in the ui config")
+	}
+	return config.ScenarioConfigsPath, nil
+}
+
+// ListScenarioConfigs lists the scenario configs of a folder (scenarioConfigsPath when empty)
+// with their run, outputs and the liability configs referencing them
+func (a *App) ListScenarioConfigs(folderPath string, includeArchived bool) ([]ScenarioConfigEntry, error) {
+	folder, err := a.scenarioConfigsFolder(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := scenarioConfigFiles(folder)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading scenario configs: "+err.Error())
+		return nil, err
+	}
+	if includeArchived {
+		archived, err := scenarioConfigFiles(filepath.Join(folder, scenarioArchiveDir))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		paths = append(paths, archived...)
+	}
+
+	references := a.liabilityScenarioReferences()
+	entries := make([]ScenarioConfigEntry, 0, len(paths))
+	for _, path := range paths {
+		entry, _ := a.describeScenarioConfig(path, references)
+		entries = append(entries, entry.ScenarioConfigEntry)
+	}
+	return entries, nil
+}
+
+// DescribeScenarioConfig returns a scenario config with its outputs, references and last generation
+func (a *App) DescribeScenarioConfig(path string) (*ScenarioConfigDetails, error) {
+	details, err := a.describeScenarioConfig(path, a.liabilityScenarioReferences())
+	if err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// CloneScenarioConfig copies a scenario config to newName in its folder, the next
+// config_ESG_OTF_<n>.json when empty. the clone's run_id is its file name so generating it
+// doesn't overwrite the original's scenarios. the file is copied as is but for run_id
+func (a *App) CloneScenarioConfig(path string, newName string) (*ScenarioConfigEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading scenario config: "+err.Error())
+		return nil, err
+	}
+
+	folder := filepath.Dir(path)
+	if filepath.Base(folder) == scenarioArchiveDir {
+		folder = filepath.Dir(folder)
+	}
+	if newName == "" {
+		if newName, err = nextScenarioConfigName(folder); err != nil {
+			return nil, err
+		}
+	}
+	target, err := scenarioConfigTarget(folder, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = setJSONString(data, "run_id", strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		runtime.LogError(a.ctx, "Error cloning scenario config: "+err.Error())
+		return nil, err
+	}
+
+	details, err := a.describeScenarioConfig(target, a.liabilityScenarioReferences())
+	if err != nil {
+		return nil, err
+	}
+	return &details.ScenarioConfigEntry, nil
+}
+
+// RenameScenarioConfig renames a scenario config file. its run_id and outputs are kept, liability
+// configs reference those
+func (a *App) RenameScenarioConfig(path string, newName string) (*ScenarioConfigEntry, error) {
+	if err := a.checkNotBaseScenarioConfig(path); err != nil {
+		return nil, err
+	}
+	target, err := scenarioConfigTarget(filepath.Dir(path), newName)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(path, target); err != nil {
+		runtime.LogError(a.ctx, "Error renaming scenario config: "+err.Error())
+		return nil, err
+	}
+
+	details, err := a.describeScenarioConfig(target, a.liabilityScenarioReferences())
+	if err != nil {
+		return nil, err
+	}
+	return &details.ScenarioConfigEntry, nil
+}
+
+// ArchiveScenarioConfig moves a scenario config to the archive subfolder, or back out of it
+// when restore is set. outputs stay where they are
+func (a *App) ArchiveScenarioConfig(path string, restore bool) (*ScenarioConfigEntry, error) {
+	if err := a.checkNotBaseScenarioConfig(path); err != nil {
+		return nil, err
+	}
+
+	folder := filepath.Dir(path)
+	archived := filepath.Base(folder) == scenarioArchiveDir
+	var target string
+	switch {
+	case restore && archived:
+		target = filepath.Join(filepath.Dir(folder), filepath.Base(path))
+	case !restore && !archived:
+		target = filepath.Join(folder, scenarioArchiveDir, filepath.Base(path))
+	case restore:
+		return nil, fmt.Errorf("%s is not archived", filepath.Base(path))
+	default:
+		return nil, fmt.Errorf("%s is already archived", filepath.Base(path))
+	}
+
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("%s already exists", target)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(path, target); err != nil {
+		runtime.LogError(a.ctx, "Error archiving scenario config: "+err.Error())
+		return nil, err
+	}
+
+	details, err := a.describeScenarioConfig(target, a.liabilityScenarioReferences())
+	if err != nil {
+		return nil, err
+	}
+	return &details.ScenarioConfigEntry, nil
+}
+
+// DeleteScenarioConfig deletes a scenario config, and its output folder when deleteOutputs is
+// set. outputs referenced by liability configs are never deleted
+func (a *App) DeleteScenarioConfig(path string, deleteOutputs bool) error {
+	if err := a.checkNotBaseScenarioConfig(path); err != nil {
+		return err
+	}
+
+	details, err := a.describeScenarioConfig(path, a.liabilityScenarioReferences())
+	if err != nil {
+		return err
+	}
+	if deleteOutputs && len(details.ReferencedBy) > 0 {
+		return fmt.Errorf("scenarios of %s are used by %s", details.Name, strings.Join(details.ReferencedBy, ", "))
+	}
+	if deleteOutputs && details.OutputFolder != "" {
+		if other := a.sharedOutputFolder(path, details.OutputFolder); other != "" {
+			return fmt.Errorf("%s writes to the same output folder as %s", other, details.Name)
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		runtime.LogError(a.ctx, "Error deleting scenario config: "+err.Error())
+		return err
+	}
+	if deleteOutputs && details.OutputFolder != "" {
+		if err := os.RemoveAll(details.OutputFolder); err != nil {
+			runtime.LogError(a.ctx, "Error deleting scenario outputs: "+err.Error())
+			return err
+		}
+		a.fileCache.invalidate(details.OutputFolder)
+	}
+	return nil
+}
+
+// sharedOutputFolder returns the name of another config of path's library generating into folder
+func (a *App) sharedOutputFolder(path string, folder string) string {
+	library := filepath.Dir(path)
+	if filepath.Base(library) == scenarioArchiveDir {
+		library = filepath.Dir(library)
+	}
+
+	for _, dir := range []string{library, filepath.Join(library, scenarioArchiveDir)} {
+		paths, _ := scenarioConfigFiles(dir)
+		for _, other := range paths {
+			if sameFile(other, path) {
+				continue
+			}
+			details, err := a.describeScenarioConfig(other, nil)
+			if err == nil && details.OutputFolder != "" && filepath.Clean(details.OutputFolder) == filepath.Clean(folder) {
+				return details.Name
+			}
+		}
+	}
+	return ""
+}
+
+func (a *App) checkNotBaseScenarioConfig(path string) error {
+	config, err := a.ReadUIConfig()
+	if err != nil || config.BaseScenarioConfigPath == "" {
+		return nil
+	}
+	if sameFile(path, config.BaseScenarioConfigPath) {
+		return fmt.Errorf("%s is the base scenario config", filepath.Base(path))
+	}
+	return nil
+}
+
+func (a *App) describeScenarioConfig(path string, references map[string][]string) (ScenarioConfigDetails, error) {
+	details := ScenarioConfigDetails{ScenarioConfigEntry: ScenarioConfigEntry{
+		Name:     filepath.Base(path),
+		Path:     path,
+		Archived: filepath.Base(filepath.Dir(path)) == scenarioArchiveDir,
+	}}
+	if ui, err := a.ReadUIConfig(); err == nil && ui.BaseScenarioConfigPath != "" {
+		details.Base = sameFile(path, ui.BaseScenarioConfigPath)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		details.Error = err.Error()
+		return details, err
+	}
+	details.ModifiedAt = info.ModTime().Format(time.RFC3339)
+
+	var config ScenarioConfig
+	if err := readJSON5File(path, &config); err != nil {
+		details.Error = err.Error()
+		return details, err
+	}
+	details.Config = &config
+	details.RunID = config.RunID
+	details.Asof = config.Asof
+	if config.RunID == "" {
+		return details, nil
+	}
+
+	// scenario outputs of a config that was moved to the archive are still next to the active ones
+	configPath := path
+	if details.Archived {
+		configPath = filepath.Join(filepath.Dir(filepath.Dir(path)), details.Name)
+	}
+	details.OutputFolder = scenarioOutputFolder(configPath, &config)
+
+	var generation ScenarioGenerationResult
+	if err := readJSON5File(filepath.Join(details.OutputFolder, scenarioGenerationFile), &generation); err == nil {
+		details.Generation = &generation
+	}
+
+	expected := expectedScenarioFiles(&config, details.Generation)
+	details.FilesExist = len(expected) > 0
+	for _, name := range expected {
+		file := filepath.Join(details.OutputFolder, name)
+		if _, err := os.Stat(file); err != nil {
+			details.FilesExist = false
+			details.MissingFiles = append(details.MissingFiles, name)
+			continue
+		}
+		details.ScenarioFiles = append(details.ScenarioFiles, file)
+	}
+
+	// other CSV files of the run_id folder are listed, they don't count towards FilesExist
+	seen := make(map[string]bool)
+	for _, file := range details.ScenarioFiles {
+		seen[strings.ToLower(filepath.Base(file))] = true
+	}
+	if entries, err := os.ReadDir(details.OutputFolder); err == nil {
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(name), ".csv") && !seen[strings.ToLower(name)] {
+				details.ScenarioFiles = append(details.ScenarioFiles, filepath.Join(details.OutputFolder, name))
+			}
+		}
+	}
+
+	// liability configs reading a file of this run's folder, other runs' files share the names
+	referencedBy := make(map[string]bool)
+	for file, liabilities := range references {
+		if sameFile(filepath.Dir(file), details.OutputFolder) {
+			for _, liability := range liabilities {
+				referencedBy[liability] = true
+			}
+		}
+	}
+	for liability := range referencedBy {
+		details.ReferencedBy = append(details.ReferencedBy, liability)
+	}
+	sort.Strings(details.ReferencedBy)
+	return details, nil
+}
+
+// setJSONString replaces the string value of key in a JSON document, leaving the rest of the
+// text (formatting, key order, fields the UI doesn't know) as it was
+func setJSONString(data []byte, key string, value string) ([]byte, error) {
+	pattern := regexp.MustCompile(`("` + regexp.QuoteMeta(key) + `"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	loc := pattern.FindSubmatchIndex(data)
+	if loc == nil {
+		return nil, fmt.Errorf("no %s to set", key)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := append([]byte{}, data[:loc[3]]...)
+	result = append(result, encoded...)
+	return append(result, data[loc[1]:]...), nil
+}
+
+// expectedScenarioFiles are the file names the last recorded generation wrote or, without a
+// record as ESG_deterministic.py leaves none, the names the config gives its files, see
+// esgScenarioFileNames. nil when the config can't name them
+func expectedScenarioFiles(config *ScenarioConfig, generation *ScenarioGenerationResult) []string {
+	if generation == nil || len(generation.Files) == 0 {
+		names, err := esgScenarioFileNames(config)
+		if err != nil {
+			return nil
+		}
+		return names
+	}
+	names := make([]string, 0, len(generation.Files))
+	for _, file := range generation.Files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	return names
+}
+
+// liabilityScenarioReferences maps the scenario files used by the liability configs of the ui
+// config's pathToLiabilityConfigs to the configs using them. files are keyed by their path,
+// resolved as PreflightScenarioFiles resolves them, files that aren't found are left out
+func (a *App) liabilityScenarioReferences() map[string][]string {
+	references := make(map[string][]string)
+
+	ui, err := a.ReadUIConfig()
+	if err != nil || ui.PathToLiabilityConfigs == "" {
+		return references
+	}
+	roots, _ := a.scenarioFileRoots(ui.PalmFolderPath)
+	configs, err := a.GetLiabilityConfigs(ui.PathToLiabilityConfigs)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading liability configs: "+err.Error())
+		return references
+	}
+
+	for _, config := range configs {
+		name := filepath.Base(config.DirectoryName)
+		keys := make(map[string]bool)
+		for _, ref := range liabilityScenarioFiles(&config.ConfigData) {
+			if path, _ := resolveScenarioPath(filepath.FromSlash(ref.Path), roots); path != "" {
+				keys[filepath.Clean(path)] = true
+			}
+		}
+		for key := range keys {
+			references[key] = append(references[key], name)
+		}
+	}
+	return references
+}
+
+// scenarioFileRef is a scenario file setting of a liability config
+type scenarioFileRef struct {
+	Field string // json name of the setting
+	Path  string
+}
+
+// liabilityScenarioFiles lists the scenario files a liability config reads, settings left empty
+// are skipped
+func liabilityScenarioFiles(config *LiabilityConfig) []scenarioFileRef {
+	fields := []scenarioFileRef{
+		{"sOutterLoopScenario", config.SOutterLoopScenario},
+		{"sInnerLoopScenario", config.SInnerLoopScenario},
+		{"SScenario_outterfile_external", config.SScenarioOutterfileExternal},
+		{"sScenario_innerfile_external", config.SScenarioInnerfileExternal},
+		{"sScenario_innerfile_up_external", config.SScenarioInnerfileUpExternal},
+		{"sScenario_innerfile_down_external", config.SScenarioInnerfileDownExternal},
+		{"sScenario_innerfile_up_liq_external", config.SScenarioInnerfileUpLiqExternal},
+		{"sScenario_innerfile_down_liq_external", config.SScenarioInnerfileDownLiqExternal},
+		{"sScenario_innerfile_up_liq_external_shock1", config.SScenarioInnerfileUpLiqExternalShock1},
+		{"sScenario_innerfile_down_liq_external_shock1", config.SScenarioInnerfileDownLiqExternalShock1},
+		{"sScenario_innerfile_up_liq_external_shock2", config.SScenarioInnerfileUpLiqExternalShock2},
+		{"sScenario_innerfile_down_liq_external_shock2", config.SScenarioInnerfileDownLiqExternalShock2},
+		{"sofr_outer", config.SofrOuter},
+		{"sofr_inner", config.SofrInner},
+		{"sofr_inner_u25", config.SofrInnerU25},
+		{"sofr_inner_d25", config.SofrInnerD25},
+		{"sofr_inner_liqup", config.SofrInnerLiqup},
+		{"sofr_inner_liqdown", config.SofrInnerLiqdown},
+		{"sofr_inner_liqup_u25", config.SofrInnerLiqupU25},
+		{"sofr_inner_liqup_d25", config.SofrInnerLiqupD25},
+		{"sofr_inner_liqdown_u25", config.SofrInnerLiqdownU25},
+		{"sofr_inner_liqdown_d25", config.SofrInnerLiqdownD25},
+	}
+
+	refs := fields[:0]
+	for _, field := range fields {
+		if strings.TrimSpace(field.Path) != "" {
+			refs = append(refs, field)
+		}
+	}
+	return refs
+}
+
+// scenarioConfigFiles lists the json files of a folder
+func scenarioConfigFiles(folder string) ([]string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			paths = append(paths, filepath.Join(folder, entry.Name()))
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return naturalLess(filepath.Base(paths[i]), filepath.Base(paths[j]))
+	})
+	return paths, nil
+}
+
+// naturalLess orders config_ESG_OTF_2.json before config_ESG_OTF_10.json
+func naturalLess(a string, b string) bool {
+	ma, mb := scenarioConfigVersionPattern.FindStringSubmatch(a), scenarioConfigVersionPattern.FindStringSubmatch(b)
+	if ma != nil && mb != nil {
+		va, _ := strconv.Atoi(ma[1])
+		vb, _ := strconv.Atoi(mb[1])
+		return va < vb
+	}
+	return a < b
+}
+
+// nextScenarioConfigName is config_ESG_OTF_<n>.json after the highest version in the folder
+// and its archive
+func nextScenarioConfigName(folder string) (string, error) {
+	version := 0
+	for _, dir := range []string{folder, filepath.Join(folder, scenarioArchiveDir)} {
+		paths, err := scenarioConfigFiles(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		for _, path := range paths {
+			if match := scenarioConfigVersionPattern.FindStringSubmatch(filepath.Base(path)); match != nil {
+				if n, _ := strconv.Atoi(match[1]); n > version {
+					version = n
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("config_ESG_OTF_%d.json", version+1), nil
+}
+
+// scenarioConfigTarget checks a new config file name and returns its path in folder
+func scenarioConfigTarget(folder string, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, `/\:*?"<>|`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid config name %q", name)
+	}
+	if !strings.EqualFold(filepath.Ext(name), ".json") {
+		name += ".json"
+	}
+
+	target := filepath.Join(folder, name)
+	if _, err := os.Stat(target); err == nil {
+		return "", fmt.Errorf("%s already exists", name)
+	}
+	return target, nil
+}
+
+// sameFile reports whether two paths name the same file
+func sameFile(a string, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(infoA, infoB)
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
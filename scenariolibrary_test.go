@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetJSONString(t *testing.T) {
+	data := []byte("{\n    \"Asof\": \"2024-03-29\",\n    \"run_id\" : \"config_ESG_OTF_1\",\n    \"Unknown\": {\"run\": 1}\n}\n")
+	got, err := setJSONString(data, "run_id", `config "2"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n    \"Asof\": \"2024-03-29\",\n    \"run_id\" : \"config \\\"2\\\"\",\n    \"Unknown\": {\"run\": 1}\n}\n"
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := setJSONString([]byte(`{"Asof": "2024-03-29"}`), "run_id", "x"); err == nil {
+		t.Errorf("no error for a config without run_id")
+	}
+}
+
+func TestScenarioFilesExistWithoutGenerationRecord(t *testing.T) {
+	folder := t.TempDir()
+	configPath := filepath.Join(folder, "config_ESG_OTF_1.json")
+	config := `{"run_id": "run1", "output_path": "out", "InnerShockScenarios": ["U25"], "listOfInnerProjectionMonth": [0]}`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp()
+
+	// nothing generated yet
+	details, err := app.describeScenarioConfig(configPath, map[string][]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.FilesExist {
+		t.Errorf("FilesExist with no output folder")
+	}
+
+	// files as ESG_deterministic.py names them, without a scenario_generation.json. other CSV
+	// files don't stand in for the missing ones
+	output := filepath.Join(folder, "out", "run1")
+	if err := os.MkdirAll(output, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(names ...string) {
+		t.Helper()
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(output, name), []byte("Scenario,1Y\n1,3\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write("run1_sofr_outer.csv", "run1_sofr_inner_u25.csv", "notes.csv")
+	details, err = app.describeScenarioConfig(configPath, map[string][]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.FilesExist || !reflect.DeepEqual(details.MissingFiles, []string{"run1_tr_outer.csv", "run1_tr_inner_u25.csv"}) {
+		t.Errorf("got FilesExist %v, missing %v", details.FilesExist, details.MissingFiles)
+	}
+	write("run1_tr_outer.csv", "run1_tr_inner_u25.csv")
+	details, err = app.describeScenarioConfig(configPath, map[string][]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !details.FilesExist || len(details.MissingFiles) != 0 || len(details.ScenarioFiles) != 5 {
+		t.Errorf("got FilesExist %v, missing %v, files %v", details.FilesExist, details.MissingFiles, details.ScenarioFiles)
+	}
+
+	// a recorded generation is checked file by file
+	record := ScenarioGenerationResult{Files: []GeneratedScenarioFile{
+		{Path: filepath.Join(output, "run1_sofr_outer.csv")},
+		{Path: filepath.Join(output, "run1_HW1F_TR_Outer.csv")},
+	}}
+	if err := writeJSONFile(filepath.Join(output, scenarioGenerationFile), record); err != nil {
+		t.Fatal(err)
+	}
+	details, err = app.describeScenarioConfig(configPath, map[string][]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.FilesExist || !reflect.DeepEqual(details.MissingFiles, []string{"run1_HW1F_TR_Outer.csv"}) {
+		t.Errorf("got FilesExist %v, missing %v", details.FilesExist, details.MissingFiles)
+	}
+}
+
+func TestScenarioConfigReferencedByFullPath(t *testing.T) {
+	folder := t.TempDir()
+	for _, run := range []string{"run1", "run2"} {
+		configPath := filepath.Join(folder, "config_"+run+".json")
+		if err := os.WriteFile(configPath, []byte(`{"run_id": "`+run+`", "output_path": "out"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(folder, "out", run), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// both runs write run_sofr_outer.csv style names, only run2's file is used
+	references := map[string][]string{
+		filepath.Join(folder, "out", "run2", "run1_sofr_outer.csv"): {"liability_config_3.json"},
+	}
+	app := NewApp()
+	details, err := app.describeScenarioConfig(filepath.Join(folder, "config_run1.json"), references)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.ReferencedBy) != 0 {
+		t.Errorf("run1 referenced by %v through another folder's file", details.ReferencedBy)
+	}
+	details, err = app.describeScenarioConfig(filepath.Join(folder, "config_run2.json"), references)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(details.ReferencedBy, []string{"liability_config_3.json"}) {
+		t.Errorf("run2 referenced by %v", details.ReferencedBy)
+	}
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"prismic-ui/curve"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// shock shapes
+const (
+	ShockParallel  = "parallel"  // Bps at every tenor
+	ShockTwist     = "twist"     // ShortBps at the shortest tenor, 0 at PivotTenor, LongBps at the longest
+	ShockButterfly = "butterfly" // Bps at both ends, BellyBps at PivotTenor
+	ShockKeyRate   = "keyrate"   // Bps at KeyTenor, fading to 0 at the neighbouring tenors
+	ShockCustom    = "custom"    // Points, interpolated linearly between the tenors given
+)
+
+// ShockDefinition describes a named curve shock in basis points
+type ShockDefinition struct {
+	Name       string
+	Type       string
+	Bps        float64
+	ShortBps   float64
+	LongBps    float64
+	BellyBps   float64
+	PivotTenor string
+	KeyTenor   string
+	Points     map[string]float64 // tenor to basis points
+	RampMonths int                // months the shock grades in over, its DictShockedMonth
+	Outer      bool               // listed in BaseShockScenarios
+	Inner      bool               // listed in InnerShockScenarios
+}
+
+// ShockWriteRequest replaces the shocks of a scenario config
+type ShockWriteRequest struct {
+	ConfigPath string
+	ShockFile  string   // the config's ShockInputFile when empty, shocks_<run_id>.csv if it has none
+	Tenors     []string // columns of the file, the config's tenors when empty
+	Shocks     []ShockDefinition
+}
+
+// ShockSet is the content of a ShockInputFile with the config's use of each shock
+type ShockSet struct {
+	ConfigPath string
+	ShockFile  string
+	Tenors     []string
+	Shocks     []ShockEntry
+}
+
+type ShockEntry struct {
+	Name       string
+	Bps        []float64 // per tenor
+	RampMonths int
+	Outer      bool
+	Inner      bool
+}
+
+// ReadShockDefinitions reads the ShockInputFile of a scenario config with the ramp-in and
+// outer/inner use the config gives each shock
+func (a *App) ReadShockDefinitions(configPath string) (*ShockSet, error) {
+	config, err := a.ReadScenarioConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &ShockSet{ConfigPath: configPath, ShockFile: resolveConfigPath(configPath, config.ShockInputFile)}
+	if set.ShockFile == "" {
+		return set, nil
+	}
+
+	data, err := parseCSVFile(set.ShockFile)
+	if err != nil {
+		runtime.LogError(a.ctx, "Error reading shock file: "+err.Error())
+		return nil, err
+	}
+	if len(data) == 0 {
+		return set, nil
+	}
+	set.Tenors = append(set.Tenors, data[0][1:]...)
+
+	for i, record := range data[1:] {
+		name := strings.TrimSpace(record[0])
+		if name == "" {
+			continue
+		}
+		entry := ShockEntry{
+			Name:       name,
+			Bps:        make([]float64, len(set.Tenors)),
+			RampMonths: config.DictShockedMonth[name],
+			Outer:      containsFold(config.BaseShockScenarios, name),
+			Inner:      containsFold(config.InnerShockScenarios, name),
+		}
+		for col := range set.Tenors {
+			if col+1 >= len(record) || strings.TrimSpace(record[col+1]) == "" {
+				continue
+			}
+			if entry.Bps[col], err = strconv.ParseFloat(strings.TrimSpace(record[col+1]), 64); err != nil {
+				return nil, fmt.Errorf("%s: row %d, column %s: invalid shock %q", set.ShockFile, i+2, set.Tenors[col], record[col+1])
+			}
+		}
+		set.Shocks = append(set.Shocks, entry)
+	}
+	return set, nil
+}
+
+// WriteShockDefinitions writes the shocks to the config's ShockInputFile and updates the config
+// to match: ShockInputFile, DictShockedMonth and the shocks listed in BaseShockScenarios and
+// InnerShockScenarios. unshocked base scenarios already listed are kept. a shock file that
+// already exists keeps its name column heading and, unless Tenors are given, its tenor columns.
+// the columns are written shortest tenor first
+func (a *App) WriteShockDefinitions(request ShockWriteRequest) (*ShockSet, error) {
+	config, err := a.ReadScenarioConfig(request.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the file goes next to the config unless one is named
+	shockFile := request.ShockFile
+	if shockFile == "" {
+		shockFile = config.ShockInputFile
+	}
+	if shockFile == "" {
+		if config.RunID == "" {
+			return nil, fmt.Errorf("no shock file given and the config has no run_id to name one")
+		}
+		shockFile = "shocks_" + config.RunID + ".csv"
+	}
+	shockPath := resolveConfigPath(request.ConfigPath, shockFile)
+
+	heading := "Shock"
+	labels := request.Tenors
+	if existing, err := parseCSVFile(shockPath); err == nil && len(existing) > 0 && len(existing[0]) > 1 {
+		heading = existing[0][0]
+		if len(labels) == 0 {
+			labels = existing[0][1:]
+		}
+	}
+	if len(labels) == 0 {
+		labels = shockTenors(config)
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("no tenors to write shocks at")
+	}
+	if labels, err = sortTenorLabels(labels); err != nil {
+		return nil, err
+	}
+	tenors := make([]float64, len(labels))
+	for i, label := range labels {
+		tenors[i], _ = curve.ParseTenor(label)
+	}
+
+	set := &ShockSet{ConfigPath: request.ConfigPath, ShockFile: shockPath, Tenors: labels}
+	rows := [][]string{append([]string{heading}, labels...)}
+	seen := make(map[string]bool)
+	for _, definition := range request.Shocks {
+		name := strings.TrimSpace(definition.Name)
+		if name == "" || strings.ContainsAny(name, ",\"\n") {
+			return nil, fmt.Errorf("invalid shock name %q", definition.Name)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("shock %s is defined twice", name)
+		}
+		seen[strings.ToLower(name)] = true
+		if definition.RampMonths < 0 {
+			return nil, fmt.Errorf("shock %s: negative ramp-in", name)
+		}
+
+		bps, err := shockShape(definition, tenors)
+		if err != nil {
+			return nil, fmt.Errorf("shock %s: %w", name, err)
+		}
+
+		row := []string{name}
+		for _, value := range bps {
+			row = append(row, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		rows = append(rows, row)
+		set.Shocks = append(set.Shocks, ShockEntry{Name: name, Bps: bps, RampMonths: definition.RampMonths, Outer: definition.Outer, Inner: definition.Inner})
+	}
+
+	if err := writeCSVFile(set.ShockFile, rows); err != nil {
+		runtime.LogError(a.ctx, "Error writing shock file: "+err.Error())
+		return nil, err
+	}
+
+	// the config is edited as a generic object so fields the UI doesn't know about survive
+	var raw map[string]interface{}
+	if err := readJSON5File(request.ConfigPath, &raw); err != nil {
+		return nil, err
+	}
+
+	// the config refers to the file relative to itself when it's in the same folder tree
+	raw["ShockInputFile"] = shockFile
+	if rel, err := filepath.Rel(filepath.Dir(request.ConfigPath), set.ShockFile); err == nil && !strings.HasPrefix(rel, "..") {
+		raw["ShockInputFile"] = filepath.ToSlash(rel)
+	}
+	shockedMonth := make(map[string]int)
+	for _, shock := range set.Shocks {
+		if shock.RampMonths > 0 {
+			shockedMonth[shock.Name] = shock.RampMonths
+		}
+	}
+	raw["DictShockedMonth"] = shockedMonth
+	raw["BaseShockScenarios"] = shockScenarioNames(config.BaseShockScenarios, set.Shocks, func(s ShockEntry) bool { return s.Outer })
+	raw["InnerShockScenarios"] = shockScenarioNames(config.InnerShockScenarios, set.Shocks, func(s ShockEntry) bool { return s.Inner })
+
+	if err := writeJSONFile(request.ConfigPath, raw); err != nil {
+		runtime.LogError(a.ctx, "Error updating scenario config: "+err.Error())
+		return nil, err
+	}
+	return set, nil
+}
+
+// shockScenarioNames keeps the base scenarios of a BaseShockScenarios/InnerShockScenarios list
+// and adds the shocks selected, in definition order
+func shockScenarioNames(current []string, shocks []ShockEntry, selected func(ShockEntry) bool) []string {
+	names := []string{}
+	for _, name := range current {
+		if isBaseShock(name) {
+			names = append(names, name)
+		}
+	}
+	for _, shock := range shocks {
+		if selected(shock) && !containsFold(names, shock.Name) {
+			names = append(names, shock.Name)
+		}
+	}
+	return names
+}
+
+// shockTenors are the tenors a config's scenarios are written at and its spot rate tenors, shortest first
+func shockTenors(config *ScenarioConfig) []string {
+	labels := append([]string{}, config.TenorsOfInterests...)
+	for _, rates := range []map[string]float64{config.SofrSpotRate, config.TrSpotRate} {
+		for label := range rates {
+			labels = append(labels, label)
+		}
+	}
+
+	years := make(map[string]float64)
+	var unique []string
+	for _, label := range labels {
+		t, err := curve.ParseTenor(label)
+		if err != nil {
+			continue
+		}
+		duplicate := false
+		for _, other := range unique {
+			if years[other] == t {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			years[label] = t
+			unique = append(unique, label)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool { return years[unique[i]] < years[unique[j]] })
+	return unique
+}
+
+// sortTenorLabels orders tenor labels shortest first, the order shockShape needs. a label that
+// isn't a tenor or repeats another's tenor is an error
+func sortTenorLabels(labels []string) ([]string, error) {
+	years := make(map[string]float64, len(labels))
+	sorted := make([]string, 0, len(labels))
+	for _, label := range labels {
+		t, err := curve.ParseTenor(label)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range sorted {
+			if years[other] == t {
+				return nil, fmt.Errorf("tenors %s and %s are the same", other, label)
+			}
+		}
+		years[label] = t
+		sorted = append(sorted, label)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return years[sorted[i]] < years[sorted[j]] })
+	return sorted, nil
+}
+
+// shockShape evaluates a shock definition at tenors (years, ascending)
+func shockShape(definition ShockDefinition, tenors []float64) ([]float64, error) {
+	if !sort.Float64sAreSorted(tenors) {
+		return nil, fmt.Errorf("tenors are not in ascending order")
+	}
+	bps := make([]float64, len(tenors))
+	first, last := tenors[0], tenors[len(tenors)-1]
+
+	kind := strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(definition.Type))
+	switch kind {
+	case ShockParallel, "":
+		for i := range bps {
+			bps[i] = definition.Bps
+		}
+
+	case ShockTwist, ShockButterfly:
+		pivot, err := curve.ParseTenor(definition.PivotTenor)
+		if err != nil {
+			return nil, fmt.Errorf("pivot tenor: %w", err)
+		}
+		if pivot <= first || pivot >= last {
+			return nil, fmt.Errorf("pivot tenor %s is not inside the tenors", definition.PivotTenor)
+		}
+		short, middle, long := definition.ShortBps, 0.0, definition.LongBps
+		if kind == ShockButterfly {
+			short, middle, long = definition.Bps, definition.BellyBps, definition.Bps
+		}
+		for i, t := range tenors {
+			if t <= pivot {
+				bps[i] = short + (middle-short)*(t-first)/(pivot-first)
+			} else {
+				bps[i] = middle + (long-middle)*(t-pivot)/(last-pivot)
+			}
+		}
+
+	case ShockKeyRate, "key":
+		key, err := curve.ParseTenor(definition.KeyTenor)
+		if err != nil {
+			return nil, fmt.Errorf("key tenor: %w", err)
+		}
+		i := sort.SearchFloat64s(tenors, key)
+		if i == len(tenors) || math.Abs(tenors[i]-key) > 1e-9 {
+			return nil, fmt.Errorf("key tenor %s is not one of the tenors", definition.KeyTenor)
+		}
+		bps[i] = definition.Bps
+
+	case ShockCustom:
+		if len(definition.Points) == 0 {
+			return nil, fmt.Errorf("no points")
+		}
+		points := make([]shockPoint, 0, len(definition.Points))
+		for label, value := range definition.Points {
+			t, err := curve.ParseTenor(label)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, shockPoint{years: t, bps: value})
+		}
+		sort.Slice(points, func(a, b int) bool { return points[a].years < points[b].years })
+		for i, t := range tenors {
+			bps[i] = interpolateShock(points, t)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown shock type %q", definition.Type)
+	}
+	return bps, nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteShockDefinitionsKeepsConfigAndFileLayout(t *testing.T) {
+	folder := t.TempDir()
+	configPath := filepath.Join(folder, "config_ESG_OTF_1.json")
+	config := `{
+  "run_id": "config_ESG_OTF_1",
+  "ShockInputFile": "shocks.csv",
+  "BaseShockScenarios": ["Base", "Old"],
+  "InnerShockScenarios": [],
+  "DictShockedMonth": {"Old": 6},
+  "TenorsOfInterests": ["1Y", "5Y", "10Y"],
+  "sofrSpotRate": {"1Y": 3, "10Y": 4},
+  "trSpotRate": {"1Y": 3, "10Y": 4},
+  "ESGVersion": "2.1",
+  "SeedSettings": {"stream": 3}
+}`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(folder, "shocks.csv"), []byte("Scenario,1Y,10Y\nOld,50,50\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewApp().WriteShockDefinitions(ShockWriteRequest{
+		ConfigPath: configPath,
+		Shocks:     []ShockDefinition{{Name: "Up100", Type: ShockParallel, Bps: 100, RampMonths: 12, Outer: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(folder, "shocks.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "Scenario,1Y,10Y\nUp100,100,100" {
+		t.Errorf("shock file %q, want the existing heading and tenors", got)
+	}
+
+	var raw map[string]interface{}
+	if err := readJSON5File(configPath, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["ESGVersion"] != "2.1" || !reflect.DeepEqual(raw["SeedSettings"], map[string]interface{}{"stream": float64(3)}) {
+		t.Errorf("unknown keys lost: %v", raw)
+	}
+	if !reflect.DeepEqual(raw["BaseShockScenarios"], []interface{}{"Base", "Up100"}) {
+		t.Errorf("BaseShockScenarios %v", raw["BaseShockScenarios"])
+	}
+	if !reflect.DeepEqual(raw["DictShockedMonth"], map[string]interface{}{"Up100": float64(12)}) {
+		t.Errorf("DictShockedMonth %v", raw["DictShockedMonth"])
+	}
+	if raw["ShockInputFile"] != "shocks.csv" {
+		t.Errorf("ShockInputFile %v", raw["ShockInputFile"])
+	}
+}
+
+// the shocks of testdata/esg written through WriteShockDefinitions give back its shocks.csv, in
+// basis points and shortest tenor first though the existing file lists 10Y first, and the
+// scenario files generated from them are the expected ones
+func TestWriteShockDefinitionsFixture(t *testing.T) {
+	folder := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("testdata", "esg", "config_ESG_OTF_1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(folder, "config_ESG_OTF_1.json")
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(folder, "shocks.csv"), []byte("Scenario,10Y,1Y\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp()
+	_, err = app.WriteShockDefinitions(ShockWriteRequest{
+		ConfigPath: configPath,
+		Shocks: []ShockDefinition{
+			{Name: "Up100", Type: ShockParallel, Bps: 100, RampMonths: 2, Outer: true},
+			{Name: "U25", Type: ShockParallel, Bps: 25, Inner: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(filepath.Join("testdata", "esg", "shocks.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(folder, "shocks.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("shock file\n%s\nwant\n%s", got, want)
+	}
+
+	shocks, err := readShockFile(filepath.Join(folder, "shocks.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points, _ := shocks.lookup("up100"); !reflect.DeepEqual(points, []shockPoint{{years: 1, bps: 100}, {years: 10, bps: 100}}) {
+		t.Errorf("Up100 read back as %v", points)
+	}
+
+	config, err := app.ReadScenarioConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ScenarioGenerationResult{OutputFolder: scenarioOutputFolder(configPath, config)}
+	if err := generateScenarioFiles(configPath, config, result, func(string, int, int, string) {}); err != nil {
+		t.Fatal(err)
+	}
+	checkExpectedScenarioFiles(t, result.OutputFolder)
+}
+
+func TestShockShapeNeedsAscendingTenors(t *testing.T) {
+	labels, err := sortTenorLabels([]string{"10Y", "6M", "2Y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(labels, []string{"6M", "2Y", "10Y"}) {
+		t.Errorf("sorted %v", labels)
+	}
+	if _, err := sortTenorLabels([]string{"1Y", "12M"}); err == nil {
+		t.Errorf("no error for the same tenor twice")
+	}
+
+	definition := ShockDefinition{Type: ShockKeyRate, KeyTenor: "2Y", Bps: 10}
+	if _, err := shockShape(definition, []float64{10, 0.5, 2}); err == nil {
+		t.Errorf("no error for unsorted tenors")
+	}
+	if bps, err := shockShape(definition, []float64{0.5, 2, 10}); err != nil || !reflect.DeepEqual(bps, []float64{0, 10, 0}) {
+		t.Errorf("key rate %v, %v", bps, err)
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// how a scenario's monthly values are reduced to the one number the distribution is built from
+const (
+	ReducePresentValue = "pv"    // present value at DiscountRate, month m discounted by m/12 years
+	ReduceSum          = "sum"   // undiscounted sum over the months
+	ReduceEnd          = "end"   // value at the last month
+	ReduceMonth        = "month" // value at Month
+	ReduceMax          = "max"
+	ReduceMin          = "min"
+)
+
+var (
+	defaultPercentiles = []float64{1, 5, 10, 25, 50, 75, 90, 95, 99}
+	defaultCTELevels   = []float64{70, 98}
+)
+
+const defaultHistogramBins = 20
+
+// ScenarioStatsRequest picks a metric from per scenario output files and how to summarise it
+type ScenarioStatsRequest struct {
+	Path               string // output folder, or a single file with a Scenario column
+	FilterString       string // as in ReadFiles
+	WalkSubdirectories bool
+	Column             string
+	TimeColumn         string  // month column, detected when empty
+	Reduce             string  // pv, sum (default), end, month, max or min
+	Month              int     // month used by the month reduction
+	DiscountRate       float64 // annual effective rate used by pv
+	Percentiles        []float64
+	CTELevels          []float64 // e.g. 70 and 98 (the defaults) for CTE70 and CTE98
+	LowerTail          bool      // the tail is the smallest values (e.g. surplus) instead of the largest (reserves)
+	Bins               int       // histogram bins, 20 when 0
+}
+
+type ScenarioValue struct {
+	Scenario string
+	Value    float64
+}
+
+type PercentileValue struct {
+	Percentile float64
+	Value      float64
+}
+
+// CTEValue is the average of the worst (100 - Level)% of scenarios
+type CTEValue struct {
+	Level     float64
+	Value     float64
+	TailCount int      // scenarios (fully or partly) in the tail
+	Scenarios []string // tail scenarios, worst first
+}
+
+type HistogramBin struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+// ScenarioDistribution summarises a metric across scenarios
+type ScenarioDistribution struct {
+	Column      string
+	Reduce      string
+	Count       int
+	Mean        float64
+	StdDev      float64 // sample standard deviation
+	Min         float64
+	Max         float64
+	Percentiles []PercentileValue
+	CTE         []CTEValue
+	Histogram   []HistogramBin
+	Values      []ScenarioValue // per scenario, in scenario order
+}
+
+// ScenarioStatistics computes the distribution of a metric over the scenarios of a stochastic
+// run: mean, standard deviation, percentiles, CTEs with their tail scenarios and a histogram
+func (a *App) ScenarioStatistics(request ScenarioStatsRequest) (*ScenarioDistribution, error) {
+	if request.Column == "" {
+		return nil, fmt.Errorf("no column given")
+	}
+	if request.Reduce == "" {
+		request.Reduce = ReduceSum
+	}
+	if len(request.Percentiles) == 0 {
+		request.Percentiles = defaultPercentiles
+	}
+	if len(request.CTELevels) == 0 {
+		request.CTELevels = defaultCTELevels
+	}
+	if request.Bins <= 0 {
+		request.Bins = defaultHistogramBins
+	}
+	for _, p := range append(append([]float64{}, request.Percentiles...), request.CTELevels...) {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile %g is outside 0-100", p)
+		}
+	}
+
+	// per scenario monthly values, read the same way AggregateOutput does
+	aggregation := AggregationRequest{
+		Path:               request.Path,
+		FilterString:       request.FilterString,
+		WalkSubdirectories: request.WalkSubdirectories,
+		Columns:            []string{request.Column},
+		TimeColumn:         request.TimeColumn,
+		GroupBy:            GroupByScenario,
+	}
+	tables, err := a.readAggregationTables(aggregation)
+	if err != nil {
+		return nil, err
+	}
+	monthly := make(map[string]map[string]map[int]float64)
+	for i := range tables {
+		if err := accumulateTable(&tables[i], aggregation, monthly); err != nil {
+			return nil, fmt.Errorf("%s: %w", tables[i].Name, err)
+		}
+	}
+
+	distribution := &ScenarioDistribution{Column: request.Column, Reduce: request.Reduce}
+	for scenario, columns := range monthly {
+		values, ok := columns[request.Column]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		value, ok, err := reduceScenario(values, request)
+		if err != nil {
+			return nil, err
+		}
+		if ok && !math.IsNaN(value) && !math.IsInf(value, 0) {
+			distribution.Values = append(distribution.Values, ScenarioValue{Scenario: scenario, Value: value})
+		}
+	}
+	if len(distribution.Values) == 0 {
+		return nil, fmt.Errorf("no scenario has values for %q", request.Column)
+	}
+
+	scenarios := make([]string, len(distribution.Values))
+	byScenario := make(map[string]float64, len(distribution.Values))
+	for i, value := range distribution.Values {
+		scenarios[i] = value.Scenario
+		byScenario[value.Scenario] = value.Value
+	}
+	sortGroups(scenarios)
+	for i, scenario := range scenarios {
+		distribution.Values[i] = ScenarioValue{Scenario: scenario, Value: byScenario[scenario]}
+	}
+
+	summarise(distribution, request)
+	return distribution, nil
+}
+
+// reduceScenario turns the monthly values of one scenario into a single number. ok is false
+// when the scenario has no value for the requested month
+func reduceScenario(values map[int]float64, request ScenarioStatsRequest) (float64, bool, error) {
+	months := make([]int, 0, len(values))
+	for month := range values {
+		months = append(months, month)
+	}
+	sort.Ints(months)
+
+	switch request.Reduce {
+	case ReducePresentValue:
+		pv := 0.0
+		for _, month := range months {
+			pv += values[month] / math.Pow(1+request.DiscountRate, float64(month)/12)
+		}
+		return pv, true, nil
+	case ReduceSum:
+		sum := 0.0
+		for _, month := range months {
+			sum += values[month]
+		}
+		return sum, true, nil
+	case ReduceEnd:
+		return values[months[len(months)-1]], true, nil
+	case ReduceMonth:
+		value, ok := values[request.Month]
+		return value, ok, nil
+	case ReduceMax, ReduceMin:
+		result := values[months[0]]
+		for _, month := range months[1:] {
+			if (request.Reduce == ReduceMax) == (values[month] > result) {
+				result = values[month]
+			}
+		}
+		return result, true, nil
+	}
+	return 0, false, fmt.Errorf("unknown reduction %q", request.Reduce)
+}
+
+func summarise(distribution *ScenarioDistribution, request ScenarioStatsRequest) {
+	n := len(distribution.Values)
+	sorted := make([]ScenarioValue, n)
+	copy(sorted, distribution.Values)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+
+	distribution.Count = n
+	distribution.Min = sorted[0].Value
+	distribution.Max = sorted[n-1].Value
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v.Value
+	}
+	distribution.Mean = sum / float64(n)
+	if n > 1 {
+		squares := 0.0
+		for _, v := range sorted {
+			squares += (v.Value - distribution.Mean) * (v.Value - distribution.Mean)
+		}
+		distribution.StdDev = math.Sqrt(squares / float64(n-1))
+	}
+
+	for _, p := range request.Percentiles {
+		distribution.Percentiles = append(distribution.Percentiles, PercentileValue{Percentile: p, Value: percentile(sorted, p)})
+	}
+
+	// worst first: largest values for reserves, smallest when LowerTail is set
+	worst := make([]ScenarioValue, n)
+	for i := range sorted {
+		if request.LowerTail {
+			worst[i] = sorted[i]
+		} else {
+			worst[i] = sorted[n-1-i]
+		}
+	}
+	for _, level := range request.CTELevels {
+		distribution.CTE = append(distribution.CTE, conditionalTailExpectation(worst, level))
+	}
+
+	distribution.Histogram = histogram(sorted, request.Bins)
+}
+
+// percentile interpolates linearly between the closest ranks of sorted values
+func percentile(sorted []ScenarioValue, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return sorted[lower].Value*(1-weight) + sorted[upper].Value*weight
+}
+
+// conditionalTailExpectation averages the worst (100 - level)% of scenarios. when that isn't
+// a whole number of scenarios the last one counts for its fraction, as in VM-21
+func conditionalTailExpectation(worst []ScenarioValue, level float64) CTEValue {
+	cte := CTEValue{Level: level}
+
+	tail := float64(len(worst)) * (100 - level) / 100
+	if tail <= 0 {
+		// CTE100 is the single worst scenario
+		tail = 1
+	}
+
+	sum, weight := 0.0, 0.0
+	for _, v := range worst {
+		if weight >= tail {
+			break
+		}
+		w := math.Min(1, tail-weight)
+		sum += v.Value * w
+		weight += w
+		cte.Scenarios = append(cte.Scenarios, v.Scenario)
+	}
+
+	cte.Value = sum / weight
+	cte.TailCount = len(cte.Scenarios)
+	return cte
+}
+
+// histogram counts sorted values in equal width bins between the smallest and largest value
+func histogram(sorted []ScenarioValue, bins int) []HistogramBin {
+	low, high := sorted[0].Value, sorted[len(sorted)-1].Value
+	if low == high {
+		return []HistogramBin{{Lower: low, Upper: high, Count: len(sorted)}}
+	}
+
+	width := (high - low) / float64(bins)
+	result := make([]HistogramBin, bins)
+	for i := range result {
+		result[i].Lower = low + float64(i)*width
+		result[i].Upper = low + float64(i+1)*width
+	}
+	result[bins-1].Upper = high
+
+	for _, v := range sorted {
+		bin := int((v.Value - low) / width)
+		if bin >= bins {
+			bin = bins - 1 // the maximum belongs to the last bin
+		}
+		result[bin].Count++
+	}
+	return result
+}
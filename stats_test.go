@@ -0,0 +1,160 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFileScenarioNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario string
+		ok       bool
+	}{
+		{"DebugInfo_Scenario_0.csv", "0", true},
+		{"DebugInfo_Scenario_run1_12.csv", "12", true},
+		{"DebugInfo_Scenario_my_run_0.csv", "0", true},
+		{"DebugInfo_Scenario_my_long_run_7.csv", "7", true},
+		{"my_run_LiabilityOutput_Scenario_3.csv", "3", true},
+		{"Scenario_5.csv", "5", true},
+		{"LiabilityOutput.csv", "", false},
+	}
+	for _, test := range tests {
+		scenario, ok := fileScenarioNumber(test.name)
+		if scenario != test.scenario || ok != test.ok {
+			t.Errorf("fileScenarioNumber(%q) = %q, %v, want %q, %v", test.name, scenario, ok, test.scenario, test.ok)
+		}
+	}
+}
+
+func TestScenarioStatisticsUnderscoredRunName(t *testing.T) {
+	folder := t.TempDir()
+	files := map[string]string{
+		"DebugInfo_Scenario_my_run_0.csv": "Month,Reserve\n1,10\n2,20\n",
+		"DebugInfo_Scenario_my_run_1.csv": "Month,Reserve\n1,30\n2,40\n",
+		"DebugInfo_Scenario_my_run_2.csv": "Month,Reserve\n1,50\n2,60\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(folder, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	distribution, err := NewApp().ScenarioStatistics(ScenarioStatsRequest{Path: folder, Column: "Reserve", Reduce: ReduceSum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ScenarioValue{{"0", 30}, {"1", 70}, {"2", 110}}
+	if len(distribution.Values) != len(want) {
+		t.Fatalf("got %d scenarios %v, want %v", len(distribution.Values), distribution.Values, want)
+	}
+	for i, value := range distribution.Values {
+		if value != want[i] {
+			t.Errorf("scenario %d: got %v, want %v", i, value, want[i])
+		}
+	}
+	if distribution.Mean != 70 {
+		t.Errorf("mean %g, want 70", distribution.Mean)
+	}
+}
+
+// scenario i has value i, 1 to n, in scenario order
+func testScenarioValues(n int) []ScenarioValue {
+	values := make([]ScenarioValue, n)
+	for i := range values {
+		values[i] = ScenarioValue{Scenario: strconv.Itoa(i + 1), Value: float64(i + 1)}
+	}
+	return values
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := testScenarioValues(10)
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 1},
+		{100, 10},
+		{50, 5.5},  // rank 4.5, halfway between 5 and 6
+		{25, 3.25}, // rank 2.25
+		{95, 9.55}, // rank 8.55
+	}
+	for _, test := range tests {
+		if got := percentile(sorted, test.p); math.Abs(got-test.want) > 1e-3 {
+			t.Errorf("percentile %g = %g, want %g", test.p, got, test.want)
+		}
+	}
+}
+
+func TestConditionalTailExpectation(t *testing.T) {
+	sorted := testScenarioValues(10)
+	worst := make([]ScenarioValue, len(sorted))
+	for i := range sorted {
+		worst[i] = sorted[len(sorted)-1-i]
+	}
+	tests := []struct {
+		level     float64
+		value     float64
+		scenarios []string
+	}{
+		{70, 9, []string{"10", "9", "8"}},                                     // 3 whole scenarios
+		{75, 9.2, []string{"10", "9", "8"}},                                   // 2.5 scenarios, 8 counts for half
+		{98, 10, []string{"10"}},                                              // 0.2 of a scenario is the worst one
+		{100, 10, []string{"10"}},                                             // the single worst scenario
+		{0, 5.5, []string{"10", "9", "8", "7", "6", "5", "4", "3", "2", "1"}}, // the mean
+	}
+	for _, test := range tests {
+		cte := conditionalTailExpectation(worst, test.level)
+		if math.Abs(cte.Value-test.value) > 1e-12 || !reflect.DeepEqual(cte.Scenarios, test.scenarios) || cte.TailCount != len(test.scenarios) {
+			t.Errorf("CTE%g = %g over %v (%d), want %g over %v", test.level, cte.Value, cte.Scenarios, cte.TailCount, test.value, test.scenarios)
+		}
+	}
+
+	// the tail is the smallest values when LowerTail is set
+	tests = []struct {
+		level     float64
+		value     float64
+		scenarios []string
+	}{
+		{75, 1.8, []string{"1", "2", "3"}}, // (1 + 2 + 3/2) / 2.5
+		{90, 1, []string{"1"}},
+	}
+	for _, test := range tests {
+		distribution := &ScenarioDistribution{Values: testScenarioValues(10)}
+		summarise(distribution, ScenarioStatsRequest{CTELevels: []float64{test.level}, LowerTail: true, Bins: 1})
+		cte := distribution.CTE[0]
+		if math.Abs(cte.Value-test.value) > 1e-12 || !reflect.DeepEqual(cte.Scenarios, test.scenarios) {
+			t.Errorf("lower tail CTE%g = %g over %v, want %g over %v", test.level, cte.Value, cte.Scenarios, test.value, test.scenarios)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	values := func(v ...float64) []ScenarioValue {
+		result := make([]ScenarioValue, len(v))
+		for i := range v {
+			result[i] = ScenarioValue{Scenario: strconv.Itoa(i), Value: v[i]}
+		}
+		return result
+	}
+	tests := []struct {
+		name   string
+		sorted []ScenarioValue
+		bins   int
+		want   []HistogramBin
+	}{
+		{"maximum in the last bin", values(0, 1, 2, 3, 4), 2, []HistogramBin{{0, 2, 2}, {2, 4, 3}}},
+		{"inner edge in the upper bin", values(0, 2, 2, 4), 4, []HistogramBin{{0, 1, 1}, {1, 2, 0}, {2, 3, 2}, {3, 4, 1}}},
+		{"one value", values(5, 5, 5), 3, []HistogramBin{{5, 5, 3}}},
+		{"negative values", values(-3, -2, 0), 3, []HistogramBin{{-3, -2, 1}, {-2, -1, 1}, {-1, 0, 1}}},
+	}
+	for _, test := range tests {
+		if got := histogram(test.sorted, test.bins); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// column types inferred from the values of a column
+const (
+	ColumnTypeNumber = "number"
+	ColumnTypeString = "string"
+	ColumnTypeEmpty  = "empty" // every value is blank
+)
+
+// Float64Array is a column of numbers where missing values are NaN in Go
+// and null once marshalled, since JSON has no NaN
+type Float64Array []float64
+
+func (f Float64Array) MarshalJSON() ([]byte, error) {
+	values := make([]*float64, len(f))
+	for i := range f {
+		if !math.IsNaN(f[i]) && !math.IsInf(f[i], 0) {
+			values[i] = &f[i]
+		}
+	}
+	return json.Marshal(values)
+}
+
+func (f *Float64Array) UnmarshalJSON(data []byte) error {
+	var values []*float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*f = make(Float64Array, len(values))
+	for i, v := range values {
+		if v == nil {
+			(*f)[i] = math.NaN()
+		} else {
+			(*f)[i] = *v
+		}
+	}
+	return nil
+}
+
+// TableColumn holds one column of an output table. Numeric columns fill Values,
+// string columns fill Strings
+type TableColumn struct {
+	Name    string
+	Unit    string
+	Type    string
+	Values  Float64Array
+	Strings []string
+}
+
+// OutputTable is a column oriented, typed view of an output CSV
+type OutputTable struct {
+	Path       string
+	Name       string
+	Schema     string   // name of the recognised output schema, empty if unknown
+	KeyColumns []string // columns identifying a row (time step, scenario, ...) for known schemas
+	Headers    []string
+	RowCount   int
+	Columns    []TableColumn
+}
+
+// tableSchema describes an output file layout pALM is known to produce
+type tableSchema struct {
+	Name       string
+	Pattern    *regexp.Regexp
+	KeyColumns []string // candidates, only the ones present in the file are kept
+	Unit       string   // default unit for numeric columns without one in the header
+}
+
+var knownTableSchemas = []tableSchema{
+	{
+		// one summary row of BEL results per run (valuation)
+		Name:    "sba_bel_summary",
+		Pattern: regexp.MustCompile(`^SBA_without_Equity_.*\.(csv|parquet)$`),
+		Unit:    "USD",
+	},
+	{
+		// monthly liability projection per scenario (liability analytics)
+		Name:       "liability_output",
+		Pattern:    regexp.MustCompile(`^.*_LiabilityOutput_Scenario_\d+\.(csv|parquet)$`),
+		KeyColumns: []string{"Month", "Time", "Period", "Date", "Scenario"},
+		Unit:       "USD",
+	},
+	{
+		// monthly balance sheet / capital debug output per scenario (risk analytics)
+		Name:       "debug_info",
+		Pattern:    regexp.MustCompile(`^DebugInfo_Scenario_.*\.(csv|parquet)$`),
+		KeyColumns: []string{"Month", "Time", "Period", "Date", "Scenario"},
+		Unit:       "USD",
+	},
+}
+
+func detectTableSchema(fileName string) *tableSchema {
+	for i := range knownTableSchemas {
+		if knownTableSchemas[i].Pattern.MatchString(fileName) {
+			return &knownTableSchemas[i]
+		}
+	}
+	return nil
+}
+
+// ReadTable reads a single output CSV as a typed table
+func (a *App) ReadTable(path string) (*OutputTable, error) {
+	data, err := a.parseCSVFileCached(path)
+	if err != nil {
+		return nil, err
+	}
+
+	table := newOutputTable(CSVFile{Path: path, Name: filepath.Base(path), Data: data})
+	return &table, nil
+}
+
+// ReadTables works like ReadFiles but returns typed tables, reading parquet outputs as well
+func (a *App) ReadTables(path string, filterString string, walkSubdirectories bool) ([]OutputTable, error) {
+	options := ReadFilesOptions{Filter: filterString, Formats: []string{".csv", ".parquet"}}
+	if walkSubdirectories {
+		options.MaxDepth = -1
+	}
+	files, err := a.readFiles(path, options, walkSubdirectories)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]OutputTable, 0, len(files))
+	for _, file := range files {
+		tables = append(tables, newOutputTable(file))
+	}
+
+	return tables, nil
+}
+
+// newOutputTable converts parsed CSV rows into columns, the first row being the header
+func newOutputTable(file CSVFile) OutputTable {
+	table := OutputTable{
+		Path: file.Path,
+		Name: file.Name,
+	}
+	if len(file.Data) == 0 {
+		return table
+	}
+
+	schema := detectTableSchema(file.Name)
+	if schema != nil {
+		table.Schema = schema.Name
+	}
+
+	header := file.Data[0]
+	rows := file.Data[1:]
+	table.Headers = header
+	table.RowCount = len(rows)
+
+	for col, heading := range header {
+		name, unit := splitHeaderUnit(heading)
+		cells := make([]string, len(rows))
+		for i, row := range rows {
+			if col < len(row) {
+				cells[i] = row[col]
+			}
+		}
+
+		column := newTableColumn(name, cells)
+		column.Unit = unit
+		if column.Unit == "" && column.Type == ColumnTypeNumber && schema != nil && !isKeyColumn(schema, name) {
+			column.Unit = schema.Unit
+		}
+		table.Columns = append(table.Columns, column)
+	}
+
+	if schema != nil {
+		for _, key := range schema.KeyColumns {
+			if table.Column(key) != nil {
+				table.KeyColumns = append(table.KeyColumns, key)
+			}
+		}
+	}
+
+	return table
+}
+
+func isKeyColumn(schema *tableSchema, name string) bool {
+	for _, key := range schema.KeyColumns {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTableColumn infers the column type: number when every non-blank value parses,
+// empty when every value is blank, string otherwise
+func newTableColumn(name string, cells []string) TableColumn {
+	column := TableColumn{Name: name, Type: ColumnTypeEmpty}
+
+	values := make(Float64Array, len(cells))
+	for i, cell := range cells {
+		value, blank, ok := parseNumber(cell)
+		if !ok {
+			column.Type = ColumnTypeString
+			column.Strings = cells
+			return column
+		}
+		if !blank {
+			column.Type = ColumnTypeNumber
+		}
+		values[i] = value
+	}
+
+	if column.Type == ColumnTypeNumber {
+		column.Values = values
+	}
+	return column
+}
+
+// Column returns the column with the given name (case insensitive), or nil
+func (t *OutputTable) Column(name string) *TableColumn {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+var headerUnitPattern = regexp.MustCompile(`^(.*?)\s*[(\[]([^()\[\]]+)[)\]]\s*$`)
+
+// splitHeaderUnit splits headers such as "BEL (USD)" or "Rate [%]" into name and unit
+func splitHeaderUnit(header string) (string, string) {
+	header = strings.TrimSpace(header)
+	if match := headerUnitPattern.FindStringSubmatch(header); match != nil && match[1] != "" {
+		return match[1], strings.TrimSpace(match[2])
+	}
+	return header, ""
+}
+
+var thousandsPattern = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d*)?$`)
+
+// parseNumber parses a CSV cell. blank cells and NaN markers give NaN with blank set,
+// ok is false when the cell is not a number at all. Handles thousands separators,
+// accounting style negatives "(1,234.5)" and percentages "5%"
+func parseNumber(cell string) (value float64, blank bool, ok bool) {
+	s := strings.TrimSpace(cell)
+	switch strings.ToLower(s) {
+	case "", "nan", "-nan", "na", "n/a", "#n/a", "null", "none", "-":
+		return math.NaN(), true, true
+	case "inf", "+inf", "infinity":
+		return math.Inf(1), false, true
+	case "-inf", "-infinity":
+		return math.Inf(-1), false, true
+	}
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		scale = 0.01
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	}
+
+	if thousandsPattern.MatchString(s) {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return math.NaN(), false, false
+	}
+
+	if negative {
+		value = -value
+	}
+	return value * scale, false, true
+}